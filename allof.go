@@ -0,0 +1,126 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+var (
+	resolveAllOfDisplay = flag.Bool("resolve-allof", false, "have describe show allOf schemas merged into a single schema")
+	applyAllOf          = flag.Bool("apply", false, "have resolve-allof store the merged schema in the spec instead of only printing it")
+)
+
+// Print a component schema with its allOf members merged into it. With
+// -apply the merged schema replaces the component in the spec, unless the
+// members conflict.
+func resolveAllOfComponent(filePath, name string) error {
+	unlock, err := lockFile(filePath)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	swagger, err := readSwaggerFile(filePath)
+	if err != nil {
+		return err
+	}
+	schema, ok := swagger.Components.Schemas[name]
+	if !ok {
+		return fmt.Errorf("component schema %s not found", name)
+	}
+
+	merged, conflicts := resolveAllOf(swagger, schema, "components.schemas."+name, []string{name})
+	for _, conflict := range conflicts {
+		fmt.Println("Warning:", conflict)
+	}
+	if !*applyAllOf {
+		data, err := yaml.Marshal(merged)
+		if err != nil {
+			return err
+		}
+		fmt.Print(string(data))
+		return nil
+	}
+
+	if len(conflicts) > 0 {
+		return fmt.Errorf("not applying, the allOf members of %s have %d conflicts", name, len(conflicts))
+	}
+	swagger.Components.Schemas[name] = merged
+	return writeSwaggerFile(outputFile(filePath), swagger)
+}
+
+// Merge the allOf members of a schema, and the schema's own keywords, into
+// one schema: properties and required are unioned, and members that are
+// component refs are resolved first. A property or type defined
+// differently by two members is a conflict; the first definition is kept
+// and a description of each conflict is returned. refs holds the
+// components being expanded, so that recursive schemas stop instead of
+// looping.
+func resolveAllOf(swagger *SwaggerTemplate, schema Schema, location string, refs []string) (Schema, []string) {
+	if len(schema.AllOf) == 0 {
+		return schema, nil
+	}
+
+	merged := schema
+	merged.AllOf = nil
+	merged.Properties = nil
+	merged.Required = nil
+	merged.propertyOrder = nil
+	var conflicts []string
+	members := append([]Schema{schema}, schema.AllOf...)
+	members[0].AllOf = nil
+	for i, member := range members {
+		memberLocation := location
+		if i > 0 {
+			memberLocation = fmt.Sprintf("%s.allOf[%d]", location, i-1)
+		}
+		memberRefs := refs
+		if member.Ref != "" {
+			name := strings.TrimPrefix(member.Ref, schemaRefPrefix)
+			target, ok := swagger.Components.Schemas[name]
+			if !ok || containsString(refs, name) {
+				conflicts = append(conflicts, fmt.Sprintf("%s: can't resolve $ref %s", memberLocation, member.Ref))
+				continue
+			}
+			member = target
+			memberRefs = append(refs[:len(refs):len(refs)], name)
+		}
+		member, memberConflicts := resolveAllOf(swagger, member, memberLocation, memberRefs)
+		conflicts = append(conflicts, memberConflicts...)
+
+		switch {
+		case member.Type == "":
+		case merged.Type == "":
+			merged.Type = member.Type
+		case member.Type != merged.Type:
+			conflicts = append(conflicts, fmt.Sprintf("%s: type %s conflicts with %s", memberLocation, member.Type, merged.Type))
+		}
+		for _, prop := range sortedKeys(member.Properties) {
+			existing, exists := merged.Properties[prop]
+			if exists && existing.Type != member.Properties[prop].Type {
+				conflicts = append(conflicts, fmt.Sprintf("%s: property %q is %s here but %s in an earlier member", memberLocation, prop, schemaSummary(member.Properties[prop]), schemaSummary(existing)))
+				continue
+			}
+			if merged.Properties == nil {
+				merged.Properties = make(map[string]Schema)
+			}
+			if !exists {
+				merged.Properties[prop] = member.Properties[prop]
+			}
+		}
+		for _, required := range member.Required {
+			if !containsString(merged.Required, required) {
+				merged.Required = append(merged.Required, required)
+			}
+		}
+	}
+	if merged.Type == "" && len(merged.Properties) > 0 {
+		merged.Type = "object"
+	}
+	sort.Strings(merged.Required)
+	return merged, conflicts
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// A change annotate-schema makes to a schema, given the spec's openapi
+// version for annotations that differ between 3.0 and 3.1
+type schemaAnnotation func(schema *Schema, openAPIVersion string)
+
+// Prompt for the details of an annotation and return the change it makes
+func promptAnnotation(reader *bufio.Reader, kind string) (schemaAnnotation, error) {
+	switch kind {
+	case "deprecated":
+		deprecated := strings.EqualFold(prompt(reader, "Mark as deprecated? (y/n): "), "y")
+		sunset := ""
+		if deprecated {
+			sunset = prompt(reader, "Enter the sunset date (YYYY-MM-DD, blank for none): ")
+		}
+		if sunset != "" {
+			if _, err := time.Parse("2006-01-02", sunset); err != nil {
+				return nil, fmt.Errorf("invalid sunset date %q: use YYYY-MM-DD", sunset)
+			}
+		}
+		return func(schema *Schema, _ string) {
+			schema.Deprecated = deprecated
+			schema.Sunset = sunset
+		}, nil
+	case "properties":
+		input := prompt(reader, "Enter the allowed number of properties as min-max, e.g. 1-10 or 1- (blank to clear): ")
+		min, max, err := parsePropertyBounds(input)
+		if err != nil {
+			return nil, err
+		}
+		return func(schema *Schema, _ string) {
+			schema.MinProperties = min
+			schema.MaxProperties = max
+		}, nil
+	case "const":
+		input := prompt(reader, "Enter the fixed value (JSON, or plain text for a string; blank to clear): ")
+		return constAnnotation(parseAnnotationValue(input)), nil
+	}
+	return nil, fmt.Errorf("unknown annotation %q", kind)
+}
+
+// Fix a schema to a single value. OpenAPI 3.0 has no const, so there the
+// value becomes a single-value enum instead. A nil value clears either.
+func constAnnotation(value interface{}) schemaAnnotation {
+	return func(schema *Schema, openAPIVersion string) {
+		if value == nil {
+			if schema.Const == nil && len(schema.Enum) == 1 {
+				schema.Enum = nil
+			}
+			schema.Const = nil
+			return
+		}
+		if strings.HasPrefix(openAPIVersion, "3.0") {
+			schema.Const = nil
+			schema.Enum = []interface{}{value}
+			return
+		}
+		schema.Const = value
+	}
+}
+
+// Parse a value typed at a prompt as JSON, so that 3 and true keep their
+// types, falling back to the text itself as a string. Blank input is nil.
+func parseAnnotationValue(input string) interface{} {
+	if input == "" {
+		return nil
+	}
+	var value interface{}
+	if err := decodeJSON([]byte(input), &value); err != nil {
+		return input
+	}
+	return sampleValue(value)
+}
+
+// Apply an annotation to a component schema, or a property within it
+func annotateSchema(filePath, name, property string, annotation schemaAnnotation) error {
+	unlock, err := lockFile(filePath)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	swagger, err := readSwaggerFile(filePath)
+	if err != nil {
+		return err
+	}
+
+	schema, ok := swagger.Components.Schemas[name]
+	if !ok {
+		return fmt.Errorf("component schema %q not found", name)
+	}
+	matched := editSchemaAtKeyPath(&schema, property, func(target *Schema) {
+		annotation(target, swagger.OpenAPI)
+	})
+	if !matched {
+		return fmt.Errorf("property %s not found in component schema %s", property, name)
+	}
+	swagger.Components.Schemas[name] = schema
+
+	return writeSwaggerFile(outputFile(filePath), swagger)
+}
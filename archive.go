@@ -0,0 +1,92 @@
+package main
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"strings"
+)
+
+// Read a spec file, or a single entry of a zip or tar.gz archive when the
+// name has the form archive:entry, e.g. spec.zip:openapi.yaml
+func readSpecData(filename string) ([]byte, error) {
+	archivePath, entry, ok := splitArchivePath(filename)
+	if !ok {
+		return ioutil.ReadFile(filename)
+	}
+
+	if strings.HasSuffix(strings.ToLower(archivePath), ".zip") {
+		return readZipEntry(archivePath, entry)
+	}
+	return readTarGzEntry(archivePath, entry)
+}
+
+// Split archive:entry into its parts. Only names whose part before the
+// colon is a .zip, .tar.gz or .tgz file count, so ordinary paths are left
+// alone.
+func splitArchivePath(filename string) (string, string, bool) {
+	lower := strings.ToLower(filename)
+	for _, ext := range []string{".zip:", ".tar.gz:", ".tgz:"} {
+		if i := strings.Index(lower, ext); i >= 0 {
+			split := i + len(ext) - 1
+			return filename[:split], filename[split+1:], true
+		}
+	}
+	return "", "", false
+}
+
+// Read one file from a zip archive
+func readZipEntry(archivePath, entry string) ([]byte, error) {
+	archive, err := zip.OpenReader(archivePath)
+	if err != nil {
+		return nil, err
+	}
+	defer archive.Close()
+
+	for _, file := range archive.File {
+		if path.Clean(file.Name) != path.Clean(entry) {
+			continue
+		}
+		rc, err := file.Open()
+		if err != nil {
+			return nil, err
+		}
+		defer rc.Close()
+		return ioutil.ReadAll(rc)
+	}
+	return nil, fmt.Errorf("%s not found in archive %s", entry, archivePath)
+}
+
+// Read one file from a gzip-compressed tar archive
+func readTarGzEntry(archivePath, entry string) ([]byte, error) {
+	f, err := os.Open(archivePath)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", archivePath, err)
+	}
+	defer gz.Close()
+
+	archive := tar.NewReader(gz)
+	for {
+		header, err := archive.Next()
+		if err == io.EOF {
+			return nil, fmt.Errorf("%s not found in archive %s", entry, archivePath)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("reading %s: %w", archivePath, err)
+		}
+		if header.Typeflag == tar.TypeReg && path.Clean(header.Name) == path.Clean(entry) {
+			return ioutil.ReadAll(archive)
+		}
+	}
+}
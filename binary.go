@@ -0,0 +1,54 @@
+package main
+
+import (
+	"encoding/base64"
+	"flag"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+var inferBinary = flag.Bool("infer-binary", false, "infer long base64 sample strings as format byte, and strings in fields named file or blob, or ending in a word like _file or Blob, as format binary")
+
+// Strings shorter than this are never taken to be base64, since short words
+// and identifiers often happen to be valid base64 too
+const minBase64Length = 64
+
+var base64Pattern = regexp.MustCompile(`^[A-Za-z0-9+/]+={0,2}$`)
+
+// Words that mark a string as binary data when a field name is or ends in
+// one, as with file, user_file or avatarBlob
+var binaryFieldWords = []string{"file", "blob"}
+
+// The format to infer for a sample string at a key path under
+// -infer-binary: binary for fields named like files or blobs, byte for
+// strings that are almost certainly base64, or "" for anything else
+func inferBinaryFormat(path, value string) string {
+	name := strings.TrimRight(path[strings.LastIndex(path, ".")+1:], "[]")
+	if endsWithWord(name, binaryFieldWords) {
+		return "binary"
+	}
+	if looksLikeBase64(value) {
+		return "byte"
+	}
+	return ""
+}
+
+// Report whether a string is long, padded base64 that decodes cleanly and
+// mixes digits and both letter cases, as encoded data does but text and
+// long identifiers rarely do
+func looksLikeBase64(value string) bool {
+	if len(value) < minBase64Length || len(value)%4 != 0 || !base64Pattern.MatchString(value) {
+		return false
+	}
+	if _, err := base64.StdEncoding.DecodeString(value); err != nil {
+		return false
+	}
+	var digit, upper, lower bool
+	for _, r := range value {
+		digit = digit || unicode.IsDigit(r)
+		upper = upper || unicode.IsUpper(r)
+		lower = lower || unicode.IsLower(r)
+	}
+	return digit && upper && lower
+}
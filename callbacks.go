@@ -0,0 +1,67 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Scaffold a callback on an operation: a POST request the API makes to the
+// URL given by a runtime expression, with a JSON body and a 200 response
+// for the receiver to fill in. Other callbacks, and other expressions of
+// the same callback, are kept as they are.
+func addCallback(filePath, path, method, name, expression string) error {
+	if !isValidComponentName(name) {
+		return fmt.Errorf("invalid callback name %q: use letters, digits, '.', '-' and '_' only", name)
+	}
+
+	unlock, err := lockFile(filePath)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	swagger, err := readSwaggerFile(filePath)
+	if err != nil {
+		return err
+	}
+	operation, ok := swagger.Paths[path].Operations[method]
+	if !ok {
+		return fmt.Errorf("operation %s %s not found", strings.ToUpper(method), path)
+	}
+
+	// Callbacks read from a file are generic maps, so work on those
+	callback := make(map[string]interface{})
+	if existing, ok := operation.Callbacks[name]; ok {
+		normalized, err := normalizeYAMLValue(existing)
+		if err != nil {
+			return err
+		}
+		if callback, ok = normalized.(map[string]interface{}); !ok {
+			return fmt.Errorf("callback %s is not a map of expressions", name)
+		}
+	}
+	if _, exists := callback[expression]; exists {
+		return fmt.Errorf("callback %s already has %s", name, expression)
+	}
+
+	callback[expression] = map[string]Operation{
+		"post": {
+			Summary: "Callback for " + name,
+			RequestBody: &RequestBody{
+				Content: map[string]MediaType{
+					"application/json": {Schema: Schema{Type: "object"}},
+				},
+			},
+			Responses: map[string]Response{
+				"200": {Description: "Callback received"},
+			},
+		},
+	}
+	if operation.Callbacks == nil {
+		operation.Callbacks = make(map[string]interface{})
+	}
+	operation.Callbacks[name] = callback
+	swagger.Paths[path].Operations[method] = operation
+
+	return writeSwaggerFile(outputFile(filePath), swagger)
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+var openAPIVersionPattern = regexp.MustCompile(`^v?(\d+)(?:\.(\d+))?(?:\.(\d+))?$`)
+
+// Rewrite a Swagger YAML file in canonical form so that specs from
+// different sources can be compared with a plain diff. Map keys such as
+// paths, methods and properties are always emitted sorted, so this only
+// needs to normalize the parts whose order or spelling can vary. Running it
+// on its own output changes nothing.
+func canonicalizeSwagger(filePath string) error {
+	unlock, err := lockFile(filePath)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	swagger, err := readSwaggerFile(filePath)
+	if err != nil {
+		return err
+	}
+
+	if err := canonicalizeSpec(swagger); err != nil {
+		return err
+	}
+	return writeSwaggerFile(outputFile(filePath), swagger)
+}
+
+// Normalize a spec in place
+func canonicalizeSpec(swagger *SwaggerTemplate) error {
+	version, err := normalizeOpenAPIVersion(swagger.OpenAPI)
+	if err != nil {
+		return err
+	}
+	swagger.OpenAPI = version
+
+	for path, item := range swagger.Paths {
+		sortParameters(item.Parameters)
+		for method, operation := range item.Operations {
+			sortParameters(operation.Parameters)
+
+			// Method keys are case-insensitive in practice but lowercase in OpenAPI
+			delete(item.Operations, method)
+			item.Operations[strings.ToLower(method)] = operation
+		}
+		swagger.Paths[path] = item
+	}
+	return nil
+}
+
+// Parameter order carries no meaning, so sort by location then name
+func sortParameters(params []Parameter) {
+	sort.SliceStable(params, func(i, j int) bool {
+		a, b := params[i], params[j]
+		if a.In != b.In {
+			return a.In < b.In
+		}
+		return a.Name < b.Name
+	})
+}
+
+// Normalize an openapi version string to its full major.minor.patch form,
+// e.g. "3.0" and "v3.0.0" both become "3.0.0"
+func normalizeOpenAPIVersion(version string) (string, error) {
+	match := openAPIVersionPattern.FindStringSubmatch(strings.TrimSpace(version))
+	if match == nil {
+		return "", fmt.Errorf("unrecognized openapi version %q", version)
+	}
+	for i := 2; i <= 3; i++ {
+		if match[i] == "" {
+			match[i] = "0"
+		}
+	}
+	return match[1] + "." + match[2] + "." + match[3], nil
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"strings"
+)
+
+// Write Markdown release notes describing the changes between two specs,
+// to outputPath or to stdout when it is empty
+func changelogSwagger(oldPath, newPath, outputPath string) error {
+	oldSwagger, err := readSwaggerFile(oldPath)
+	if err != nil {
+		return err
+	}
+	newSwagger, err := readSwaggerFile(newPath)
+	if err != nil {
+		return err
+	}
+
+	changelog := formatChangelog(DiffSpecs(oldSwagger, newSwagger), oldSwagger, newSwagger)
+	if outputPath == "" {
+		fmt.Print(changelog)
+		return nil
+	}
+	if err := ioutil.WriteFile(outputPath, []byte(changelog), 0644); err != nil {
+		return err
+	}
+	fmt.Println("Changelog written successfully.")
+	return nil
+}
+
+// Format a diff as Markdown with a section per kind of change. The specs
+// supply the operations of paths that were added or removed as a whole.
+func formatChangelog(result DiffResult, oldSwagger, newSwagger *SwaggerTemplate) string {
+	var added, removed, modified []string
+	for _, path := range result.AddedPaths {
+		for _, method := range sortedKeys(newSwagger.Paths[path].Operations) {
+			added = append(added, changelogEndpoint(path, method, newSwagger.Paths[path].Operations[method].Summary))
+		}
+	}
+	for _, path := range result.RemovedPaths {
+		for _, method := range sortedKeys(oldSwagger.Paths[path].Operations) {
+			removed = append(removed, changelogEndpoint(path, method, oldSwagger.Paths[path].Operations[method].Summary))
+		}
+	}
+	for _, change := range result.ChangedOperations {
+		switch change.Change {
+		case "added":
+			added = append(added, changelogEndpoint(change.Path, change.Method, newSwagger.Paths[change.Path].Operations[change.Method].Summary))
+		case "removed":
+			removed = append(removed, changelogEndpoint(change.Path, change.Method, oldSwagger.Paths[change.Path].Operations[change.Method].Summary))
+		case "modified":
+			entry := changelogEndpoint(change.Path, change.Method, "")
+			for _, detail := range change.Details {
+				entry += "\n  - " + detail
+			}
+			modified = append(modified, entry)
+		}
+	}
+
+	var breaking []string
+	for _, change := range result.BreakingChanges {
+		if change.Method == "" {
+			breaking = append(breaking, fmt.Sprintf("`%s`: %s", change.Path, change.Description))
+		} else {
+			breaking = append(breaking, fmt.Sprintf("`%s %s`: %s", strings.ToUpper(change.Method), change.Path, change.Description))
+		}
+	}
+
+	var sb strings.Builder
+	title, _ := newSwagger.Info["title"].(string)
+	version := fmt.Sprint(newSwagger.Info["version"])
+	fmt.Fprintf(&sb, "# Changelog: %s %s\n", title, version)
+	writeChangelogSection(&sb, "Added Endpoints", added)
+	writeChangelogSection(&sb, "Removed Endpoints", removed)
+	writeChangelogSection(&sb, "Modified Endpoints", modified)
+	writeChangelogSection(&sb, "Breaking Changes", breaking)
+	return sb.String()
+}
+
+// Describe an endpoint as a bullet point entry
+func changelogEndpoint(path, method, summary string) string {
+	entry := fmt.Sprintf("`%s %s`", strings.ToUpper(method), path)
+	if summary != "" {
+		entry += " - " + summary
+	}
+	return entry
+}
+
+// Write a Markdown section with one bullet per entry
+func writeChangelogSection(sb *strings.Builder, heading string, entries []string) {
+	fmt.Fprintf(sb, "\n## %s\n\n", heading)
+	if len(entries) == 0 {
+		sb.WriteString("None.\n")
+		return
+	}
+	for _, entry := range entries {
+		fmt.Fprintf(sb, "- %s\n", entry)
+	}
+}
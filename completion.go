@@ -0,0 +1,98 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+)
+
+// The actions runAction knows, in the order the prompt lists them. exit
+// only applies to the interactive prompt, so it isn't included.
+var actionNames = []string{
+	"view", "create", "update", "add-callback", "add-encoding", "add-link", "add-operation-server",
+	"add-path-parameter", "annotate-schema", "apply-standard-responses", "canonicalize", "changelog",
+	"check-examples", "check-refs", "completion", "convert", "curl", "describe", "diff", "doctor",
+	"edit", "enums", "export-jsonschema", "export-postman", "export-ts", "extract", "find",
+	"import-graphql", "import-har", "import-postman", "import-proto", "import-sql", "internalize",
+	"lint", "markdown", "merge", "mock", "normalize-responses", "operation-ids", "patch",
+	"rename-schema", "resolve-allof", "serve", "set-info", "set-servers", "test-response", "validate",
+	"validate-dir",
+}
+
+// List the actions for the invalid action message, e.g. 'view', 'create',
+// or 'exit'
+func formatActionList() string {
+	quoted := make([]string, len(actionNames))
+	for i, name := range actionNames {
+		quoted[i] = "'" + name + "'"
+	}
+	return strings.Join(quoted, ", ") + ", or 'exit'"
+}
+
+const bashCompletion = `_yamlconvertor() {
+    local cur=${COMP_WORDS[COMP_CWORD]} prev=${COMP_WORDS[COMP_CWORD-1]}
+    case " %[3]s " in
+    *" $prev "*)
+        COMPREPLY=($(compgen -f -- "$cur"))
+        return
+        ;;
+    esac
+    if [[ $prev == completion ]]; then
+        COMPREPLY=($(compgen -W "bash zsh" -- "$cur"))
+    elif [[ $cur == -* ]]; then
+        COMPREPLY=($(compgen -W "%[2]s" -- "$cur"))
+    else
+        COMPREPLY=($(compgen -W "%[1]s" -- "$cur"))
+    fi
+}
+complete -o default -F _yamlconvertor yamlconvertor
+`
+
+const zshCompletion = `#compdef yamlconvertor
+
+_yamlconvertor() {
+    local -a actions flags valueflags
+    actions=(%[1]s)
+    flags=(%[2]s)
+    valueflags=(%[3]s)
+    if [[ ${words[CURRENT-1]} == completion ]]; then
+        compadd bash zsh
+    elif (( ${valueflags[(Ie)${words[CURRENT-1]}]} )); then
+        _files
+    elif [[ $PREFIX == -* ]]; then
+        compadd -a flags
+    else
+        compadd -a actions
+    fi
+}
+
+if [[ $funcstack[1] == _yamlconvertor ]]; then
+    _yamlconvertor "$@"
+else
+    compdef _yamlconvertor yamlconvertor
+fi
+`
+
+// Print a script completing the actions and flags for bash or zsh. Flags
+// that take a value complete file names after them.
+func printCompletion(shell string) error {
+	var flags, valueFlags []string
+	flag.VisitAll(func(f *flag.Flag) {
+		flags = append(flags, "-"+f.Name)
+		if boolFlag, ok := f.Value.(interface{ IsBoolFlag() bool }); !ok || !boolFlag.IsBoolFlag() {
+			valueFlags = append(valueFlags, "-"+f.Name)
+		}
+	})
+
+	var script string
+	switch shell {
+	case "bash":
+		script = bashCompletion
+	case "zsh":
+		script = zshCompletion
+	default:
+		return fmt.Errorf("unsupported shell %q, expected bash or zsh", shell)
+	}
+	fmt.Printf(script, strings.Join(actionNames, " "), strings.Join(flags, " "), strings.Join(valueFlags, " "))
+	return nil
+}
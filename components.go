@@ -0,0 +1,185 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"gopkg.in/yaml.v2"
+)
+
+const schemaRefPrefix = "#/components/schemas/"
+
+var (
+	extractComponent = flag.Bool("extract-component", false, "have update store the generated response schema under components.schemas and reference it with $ref")
+	componentName    = flag.String("component-name", "", "name for the component schema extracted with -extract-component, instead of prompting")
+	extractNestedMin = flag.Int("extract-nested-min", 0, "with -extract-component, also extract nested objects with at least this many properties into components named after their property (0 to keep them inline)")
+)
+
+// Move a generated response schema into components.schemas and return the
+// $ref schema that replaces it. The name defaults to one derived from the
+// path and method; the user may override it, either with -component-name or
+// at the prompt, but only with a name that is free or holds the same schema.
+func extractResponseComponent(swagger *SwaggerTemplate, path, method, status, mediaType string, schema Schema, reader *bufio.Reader) (Schema, error) {
+	// Re-extracting for the same response reuses the component it already refers to
+	current := ""
+	if operation, ok := swagger.Paths[path].Operations[method]; ok {
+		current = strings.TrimPrefix(operation.Responses[status].Content[mediaType].Schema.Ref, schemaRefPrefix)
+	}
+
+	name := *componentName
+	if name == "" {
+		suggested := uniqueComponentName(swagger, componentBaseName(path, method, "Response"), schema, current)
+		name = prompt(reader, fmt.Sprintf("Enter component schema name (blank for %s): ", suggested))
+		if name == "" {
+			name = suggested
+		}
+	}
+	if !isValidComponentName(name) {
+		return Schema{}, fmt.Errorf("invalid component name %q: use letters, digits, '.', '-' and '_' only", name)
+	}
+	if existing, taken := swagger.Components.Schemas[name]; taken && name != current && !sameSchema(existing, schema) {
+		return Schema{}, fmt.Errorf("component schema %s already exists with a different schema; choose another name", name)
+	}
+
+	if swagger.Components.Schemas == nil {
+		swagger.Components.Schemas = make(map[string]Schema)
+	}
+	swagger.Components.Schemas[name] = schema
+	return Schema{Ref: schemaRefPrefix + name}, nil
+}
+
+// Replace nested objects in a schema's properties and array items that have
+// at least -extract-nested-min properties with $refs to components of their
+// own, innermost first. Components are named after the property holding
+// them, with an Item suffix for array items; identical objects share one.
+func extractNestedComponents(swagger *SwaggerTemplate, schema Schema) Schema {
+	if *extractNestedMin <= 0 {
+		return schema
+	}
+
+	// Sorted so that name clashes resolve the same way every run
+	for _, name := range sortedKeys(schema.Properties) {
+		schema.Properties[name] = extractNestedSchema(swagger, schema.Properties[name], capitalize(name))
+	}
+	if schema.Items != nil {
+		items := extractNestedComponents(swagger, *schema.Items)
+		schema.Items = &items
+	}
+	return schema
+}
+
+// Extract a single nested schema and its own nested objects, using base to
+// name its component
+func extractNestedSchema(swagger *SwaggerTemplate, schema Schema, base string) Schema {
+	if schema.Type == "array" && schema.Items != nil {
+		items := extractNestedSchema(swagger, *schema.Items, base+"Item")
+		schema.Items = &items
+		return schema
+	}
+
+	schema = extractNestedComponents(swagger, schema)
+	if schema.Type != "object" || len(schema.Properties) < *extractNestedMin || !isValidComponentName(base) {
+		return schema
+	}
+
+	name := uniqueComponentName(swagger, base, schema, "")
+	if swagger.Components.Schemas == nil {
+		swagger.Components.Schemas = make(map[string]Schema)
+	}
+	swagger.Components.Schemas[name] = schema
+	return Schema{Ref: schemaRefPrefix + name}
+}
+
+// Derive a component name from a path and method, e.g. GET /pets/{id}
+// becomes PetsIdGetResponse for the suffix "Response"
+func componentBaseName(path, method, suffix string) string {
+	var sb strings.Builder
+	for _, word := range strings.FieldsFunc(path, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		sb.WriteString(capitalize(word))
+	}
+	if sb.Len() == 0 {
+		sb.WriteString("Root")
+	}
+	sb.WriteString(capitalize(strings.ToLower(method)))
+	sb.WriteString(suffix)
+	return sb.String()
+}
+
+// Pick the first free name among base, base2, base3 and so on. A name is
+// free when it's unused, already holds an identical schema, or is the
+// component the caller is replacing.
+func uniqueComponentName(swagger *SwaggerTemplate, base string, schema Schema, current string) string {
+	for i := 1; ; i++ {
+		name := base
+		if i > 1 {
+			name = base + strconv.Itoa(i)
+		}
+		existing, taken := swagger.Components.Schemas[name]
+		if !taken || name == current || sameSchema(existing, schema) {
+			return name
+		}
+	}
+}
+
+// Pick the first unused name among base, base2, base3 and so on, and hold
+// it with an empty schema until the caller stores the real one. Unlike
+// uniqueComponentName, a name holding a schema is never reused, since the
+// schema to store isn't known yet.
+func reserveComponentName(swagger *SwaggerTemplate, base string) string {
+	for i := 1; ; i++ {
+		name := base
+		if i > 1 {
+			name = base + strconv.Itoa(i)
+		}
+		if _, taken := swagger.Components.Schemas[name]; !taken {
+			swagger.Components.Schemas[name] = Schema{}
+			return name
+		}
+	}
+}
+
+// Report whether two schemas are written out the same, ignoring property
+// order, which schemas read from a file have and generated ones may not
+func sameSchema(a, b Schema) bool {
+	var values [2]interface{}
+	for i, schema := range []Schema{a, b} {
+		data, err := yaml.Marshal(schema)
+		if err != nil {
+			return false
+		}
+		if err := yaml.Unmarshal(data, &values[i]); err != nil {
+			return false
+		}
+	}
+	return reflect.DeepEqual(values[0], values[1])
+}
+
+// Report whether a name is allowed as a components key by OpenAPI
+func isValidComponentName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for _, r := range name {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && !strings.ContainsRune(".-_", r) {
+			return false
+		}
+	}
+	return true
+}
+
+// Upper-case the first letter of a word
+func capitalize(word string) string {
+	if word == "" {
+		return word
+	}
+	runes := []rune(word)
+	runes[0] = unicode.ToUpper(runes[0])
+	return string(runes)
+}
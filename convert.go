@@ -0,0 +1,192 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+var (
+	minifyOutput = flag.Bool("minify", false, "write JSON specs and export-postman collections compactly, without indentation; a spec written to - is then JSON too")
+	stripDocs    = flag.Bool("strip-docs", false, "have convert drop human-readable fields such as descriptions, summaries, titles and examples; required descriptions are kept but emptied")
+)
+
+// Convert a spec between YAML and JSON. The output format follows the
+// extension of the target file. -minify writes compact JSON, so it needs a
+// .json target or -, and reports how much smaller the file is than the
+// input.
+func convertSwagger(filePath, target string) error {
+	if *minifyOutput && target != "-" && !strings.EqualFold(filepath.Ext(target), ".json") {
+		return fmt.Errorf("-minify writes JSON, so the target must end in .json or be -, not %s", target)
+	}
+	swagger, err := readSwaggerFile(filePath)
+	if err != nil {
+		return err
+	}
+	if *stripDocs {
+		stripDocumentation(swagger)
+	}
+	if err := writeSwaggerFile(target, swagger); err != nil {
+		return err
+	}
+	if !*minifyOutput || target == "-" {
+		return nil
+	}
+
+	written, err := os.Stat(target)
+	if err != nil {
+		return err
+	}
+	if info, err := os.Stat(filePath); err == nil && info.Size() > 0 {
+		fmt.Printf("Wrote %d bytes, %.0f%% smaller than the %d-byte input.\n", written.Size(), 100*(1-float64(written.Size())/float64(info.Size())), info.Size())
+	} else {
+		fmt.Printf("Wrote %d bytes.\n", written.Size())
+	}
+	return nil
+}
+
+// Remove the human-readable parts of a spec that tools don't need, along
+// with the samples -keep-sample stored. Response descriptions are required
+// by OpenAPI, so they are emptied rather than removed.
+func stripDocumentation(swagger *SwaggerTemplate) {
+	delete(swagger.Info, "description")
+	for i := range swagger.Servers {
+		swagger.Servers[i].Description = ""
+	}
+
+	for _, path := range sortedKeys(swagger.Paths) {
+		for i := range swagger.Paths[path].Parameters {
+			swagger.Paths[path].Parameters[i].Description = ""
+		}
+		for _, method := range sortedKeys(swagger.Paths[path].Operations) {
+			operation := swagger.Paths[path].Operations[method]
+			operation.Summary = ""
+			operation.Description = ""
+			delete(operation.Extensions, "x-sample")
+			for i := range operation.Servers {
+				operation.Servers[i].Description = ""
+			}
+			for i := range operation.Parameters {
+				operation.Parameters[i].Description = ""
+			}
+			if operation.RequestBody != nil {
+				operation.RequestBody.Description = ""
+				stripContentExamples(operation.RequestBody.Content)
+			}
+			for status, response := range operation.Responses {
+				response.Description = ""
+				stripContentExamples(response.Content)
+				for name, link := range response.Links {
+					link.Description = ""
+					if link.Server != nil {
+						server := *link.Server
+						server.Description = ""
+						link.Server = &server
+					}
+					response.Links[name] = link
+				}
+				operation.Responses[status] = response
+			}
+			swagger.Paths[path].Operations[method] = operation
+		}
+	}
+
+	walkSpecSchemas(swagger, func(schema *Schema, location string) {
+		schema.Title = ""
+		schema.Description = ""
+		schema.Example = nil
+	})
+}
+
+// Remove the examples of each media type in a content map
+func stripContentExamples(content map[string]MediaType) {
+	for mediaType, media := range content {
+		media.Example = nil
+		content[mediaType] = media
+	}
+}
+
+// Marshal a spec as YAML, or as indented JSON when asJSON is set. JSON
+// keys keep the same order as the YAML output. With -group-by-tag, paths
+// are written in tag groups.
+func marshalSwagger(swagger *SwaggerTemplate, asJSON bool) ([]byte, error) {
+	data, err := yaml.Marshal(swagger)
+	if err != nil || (!asJSON && !*groupByTag) {
+		return data, err
+	}
+
+	var ordered yaml.MapSlice
+	if err := yaml.Unmarshal(data, &ordered); err != nil {
+		return nil, err
+	}
+	if *groupByTag {
+		groups := tagGroups(swagger)
+		orderPathsByTag(ordered, groups)
+		if !asJSON {
+			data, err := yaml.Marshal(ordered)
+			if err != nil {
+				return nil, err
+			}
+			return commentTagGroups(data, groups)
+		}
+	}
+	var buf bytes.Buffer
+	if err := writeOrderedJSON(&buf, ordered); err != nil {
+		return nil, err
+	}
+
+	var indented bytes.Buffer
+	if err := json.Indent(&indented, buf.Bytes(), "", "  "); err != nil {
+		return nil, err
+	}
+	indented.WriteByte('\n')
+	return indented.Bytes(), nil
+}
+
+// Encode a value decoded from YAML as JSON, keeping the key order of
+// mappings decoded as yaml.MapSlice
+func writeOrderedJSON(buf *bytes.Buffer, value interface{}) error {
+	switch v := value.(type) {
+	case yaml.MapSlice:
+		buf.WriteByte('{')
+		for i, item := range v {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			key, err := json.Marshal(fmt.Sprint(item.Key))
+			if err != nil {
+				return err
+			}
+			buf.Write(key)
+			buf.WriteByte(':')
+			if err := writeOrderedJSON(buf, item.Value); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte('}')
+	case []interface{}:
+		buf.WriteByte('[')
+		for i, item := range v {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			if err := writeOrderedJSON(buf, item); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte(']')
+	default:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return err
+		}
+		buf.Write(data)
+	}
+	return nil
+}
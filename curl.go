@@ -0,0 +1,94 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// Matches a {param} in a path template
+var pathParamPattern = regexp.MustCompile(`\{([^}]+)\}`)
+
+// Print a curl command for every operation, or for one operation when path
+// is given, one command per line
+func printCurlCommands(filePath, path, method string) error {
+	swagger, err := readSwaggerFile(filePath)
+	if err != nil {
+		return err
+	}
+
+	if path != "" {
+		operation, ok := swagger.Paths[path].Operations[method]
+		if !ok {
+			return fmt.Errorf("operation %s %s not found", strings.ToUpper(method), path)
+		}
+		operation.Parameters = effectiveParameters(swagger.Paths[path], operation)
+		fmt.Println(curlCommand(swagger, path, method, operation))
+		return nil
+	}
+
+	for _, path := range sortedKeys(swagger.Paths) {
+		for _, method := range sortedKeys(swagger.Paths[path].Operations) {
+			operation := swagger.Paths[path].Operations[method]
+			operation.Parameters = effectiveParameters(swagger.Paths[path], operation)
+			fmt.Println(curlCommand(swagger, path, method, operation))
+		}
+	}
+	return nil
+}
+
+// Build the curl command for an operation. Path parameters and required
+// query and header parameters are filled with <name> placeholders, and the
+// body comes from the request body's example when there is one.
+func curlCommand(swagger *SwaggerTemplate, path, method string, operation Operation) string {
+	base := "http://localhost"
+	if len(operation.Servers) > 0 {
+		base = operation.Servers[0].URL
+	} else if len(swagger.Servers) > 0 {
+		base = swagger.Servers[0].URL
+	}
+
+	target := strings.TrimSuffix(base, "/") + pathParamPattern.ReplaceAllString(path, "<$1>")
+	var query []string
+	args := []string{"curl", "-X", strings.ToUpper(method)}
+	for _, param := range operation.Parameters {
+		if !param.Required {
+			continue
+		}
+		switch param.In {
+		case "query":
+			query = append(query, url.QueryEscape(param.Name)+"=<"+param.Name+">")
+		case "header":
+			args = append(args, "-H", shellQuote(param.Name+": <"+param.Name+">"))
+		}
+	}
+	if len(query) > 0 {
+		target += "?" + strings.Join(query, "&")
+	}
+
+	if operation.RequestBody != nil {
+		if media, ok := operation.RequestBody.Content["application/json"]; ok {
+			example, ok := media.Example, media.Example != nil
+			if !ok {
+				example, ok = schemaExample(media.Schema)
+			}
+			if ok {
+				if normalized, err := normalizeYAMLValue(example); err == nil {
+					if raw, err := json.Marshal(normalized); err == nil {
+						args = append(args, "-H", shellQuote("Content-Type: application/json"), "-d", shellQuote(string(raw)))
+					}
+				}
+			}
+		}
+	}
+
+	args = append(args, shellQuote(target))
+	return strings.Join(args, " ")
+}
+
+// Quote a string for a POSIX shell
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
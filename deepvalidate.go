@@ -0,0 +1,30 @@
+package main
+
+import (
+	"errors"
+	"flag"
+)
+
+var deepValidate = flag.Bool("deep-validate", false, "also run validate and validate-dir specs through the kin-openapi validator; needs a build with -tags kinopenapi")
+
+// The kin-openapi validator, set by deepvalidate_kin.go when the tool is
+// built with -tags kinopenapi, so the dependency is only needed by those
+// who want it. Takes the spec as JSON and returns the validator's errors.
+var deepValidator func(data []byte) []string
+
+// Run a spec through the deep validator when -deep-validate is on,
+// returning the validator's errors verbatim
+func deepValidateSwagger(swagger *SwaggerTemplate) ([]string, error) {
+	if !*deepValidate {
+		return nil, nil
+	}
+	if deepValidator == nil {
+		return nil, errors.New("-deep-validate needs kin-openapi: rebuild with go get github.com/getkin/kin-openapi && go build -tags kinopenapi")
+	}
+
+	data, err := marshalSwagger(swagger, true)
+	if err != nil {
+		return nil, err
+	}
+	return deepValidator(data), nil
+}
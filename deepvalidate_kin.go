@@ -0,0 +1,37 @@
+//go:build kinopenapi
+
+package main
+
+import (
+	"errors"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+func init() {
+	deepValidator = kinValidate
+}
+
+// Load and validate a JSON spec with kin-openapi, including format checks,
+// returning one entry per error it reports
+func kinValidate(data []byte) []string {
+	loader := openapi3.NewLoader()
+	doc, err := loader.LoadFromData(data)
+	if err != nil {
+		return []string{"kin-openapi: " + err.Error()}
+	}
+
+	err = doc.Validate(loader.Context, openapi3.EnableSchemaFormatValidation())
+	if err == nil {
+		return nil
+	}
+	var multi openapi3.MultiError
+	if !errors.As(err, &multi) {
+		return []string{"kin-openapi: " + err.Error()}
+	}
+	var problems []string
+	for _, e := range multi {
+		problems = append(problems, "kin-openapi: "+e.Error())
+	}
+	return problems
+}
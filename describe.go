@@ -0,0 +1,166 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Print one operation in detail: its summary, description, parameters,
+// request body and responses with their schemas. With -resolve-allof,
+// allOf schemas are shown merged, without changing the spec.
+func describeOperation(filePath, path, method string) error {
+	swagger, err := readSwaggerFile(filePath)
+	if err != nil {
+		return err
+	}
+
+	item, ok := swagger.Paths[path]
+	if !ok {
+		return fmt.Errorf("path %s not found", path)
+	}
+	operation, ok := item.Operations[method]
+	if !ok {
+		return fmt.Errorf("method %s not found for path %s", strings.ToUpper(method), path)
+	}
+
+	operation.Parameters = effectiveParameters(item, operation)
+	if *resolveAllOfDisplay {
+		walkOperationSchemas(&operation, fmt.Sprintf("paths.%s.%s", path, method), func(schema *Schema, location string) {
+			if len(schema.AllOf) == 0 {
+				return
+			}
+			merged, conflicts := resolveAllOf(swagger, *schema, location, nil)
+			for _, conflict := range conflicts {
+				fmt.Println("Warning:", conflict)
+			}
+			*schema = merged
+		})
+	}
+	fmt.Print(formatOperation(path, method, operation))
+	return nil
+}
+
+// Format an operation as indented text
+func formatOperation(path, method string, operation Operation) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%s %s\n", strings.ToUpper(method), path)
+	if operation.Summary != "" {
+		fmt.Fprintf(&sb, "  Summary: %s\n", operation.Summary)
+	}
+	if operation.Description != "" {
+		fmt.Fprintf(&sb, "  Description: %s\n", operation.Description)
+	}
+	if len(operation.Tags) > 0 {
+		fmt.Fprintf(&sb, "  Tags: %s\n", strings.Join(operation.Tags, ", "))
+	}
+	if operation.Deprecated {
+		sb.WriteString("  Deprecated\n")
+	}
+
+	if len(operation.Parameters) > 0 {
+		sb.WriteString("  Parameters:\n")
+		for _, param := range operation.Parameters {
+			required := ""
+			if param.Required {
+				required = ", required"
+			}
+			paramType := "any"
+			if param.Schema != nil {
+				paramType = schemaSummary(*param.Schema)
+			}
+			fmt.Fprintf(&sb, "    %s (%s%s): %s\n", param.Name, param.In, required, paramType)
+			if param.Description != "" {
+				fmt.Fprintf(&sb, "      %s\n", param.Description)
+			}
+		}
+	}
+
+	if operation.RequestBody != nil {
+		if operation.RequestBody.Required {
+			sb.WriteString("  Request body (required):\n")
+		} else {
+			sb.WriteString("  Request body:\n")
+		}
+		if operation.RequestBody.Description != "" {
+			fmt.Fprintf(&sb, "    %s\n", operation.RequestBody.Description)
+		}
+		formatContent(&sb, operation.RequestBody.Content, "    ")
+	}
+
+	sb.WriteString("  Responses:\n")
+	for _, status := range sortedKeys(operation.Responses) {
+		response := operation.Responses[status]
+		fmt.Fprintf(&sb, "    %s: %s\n", status, response.Description)
+		formatContent(&sb, response.Content, "      ")
+	}
+	return sb.String()
+}
+
+// Format each media type of a content map with its schema
+func formatContent(sb *strings.Builder, content map[string]MediaType, indent string) {
+	for _, mediaType := range sortedKeys(content) {
+		fmt.Fprintf(sb, "%s%s:\n", indent, mediaType)
+		formatSchema(sb, content[mediaType].Schema, indent+"  ")
+	}
+}
+
+// Format a schema as an indented tree, one line per property or item
+func formatSchema(sb *strings.Builder, schema Schema, indent string) {
+	fmt.Fprintf(sb, "%s%s\n", indent, schemaSummary(schema))
+	formatSchemaMembers(sb, schema, indent+"  ")
+}
+
+// Format the properties or items of a schema
+func formatSchemaMembers(sb *strings.Builder, schema Schema, indent string) {
+	for _, name := range sortedKeys(schema.Properties) {
+		prop := schema.Properties[name]
+		fmt.Fprintf(sb, "%s%s: %s\n", indent, name, schemaSummary(prop))
+		formatSchemaMembers(sb, prop, indent+"  ")
+	}
+	if schema.Items != nil && (len(schema.Items.Properties) > 0 || schema.Items.Items != nil) {
+		fmt.Fprintf(sb, "%sitems: %s\n", indent, schemaSummary(*schema.Items))
+		formatSchemaMembers(sb, *schema.Items, indent+"  ")
+	}
+}
+
+// Describe a schema's type in a few words, e.g. "array of string" or
+// "string (date-time), one of: a, b"
+func schemaSummary(schema Schema) string {
+	if schema.Ref != "" {
+		return "$ref " + schema.Ref
+	}
+
+	summary := schema.Type
+	if summary == "" {
+		summary = "any"
+	}
+	if len(schema.AllOf) > 0 {
+		summary = fmt.Sprintf("all of %d schemas", len(schema.AllOf))
+	}
+	if schema.Type == "array" && schema.Items != nil {
+		summary = "array of " + schemaSummary(*schema.Items)
+	}
+	if schema.Format != "" {
+		summary += " (" + schema.Format + ")"
+	}
+	if len(schema.Enum) > 0 {
+		values := make([]string, len(schema.Enum))
+		for i, value := range schema.Enum {
+			values[i] = fmt.Sprint(value)
+		}
+		summary += ", one of: " + strings.Join(values, ", ")
+	}
+	return summary + deprecationNote(schema)
+}
+
+// Describe whether a schema is deprecated and when it is removed, e.g.
+// ", deprecated (sunset 2025-06-30)", or "" when it isn't deprecated
+func deprecationNote(schema Schema) string {
+	if !schema.Deprecated {
+		return ""
+	}
+	if schema.Sunset != "" {
+		return ", deprecated (sunset " + schema.Sunset + ")"
+	}
+	return ", deprecated"
+}
@@ -0,0 +1,306 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+var diffJSON = flag.Bool("diff-json", false, "print the result of diff and operation-ids as JSON instead of a human-readable summary")
+
+// The structured result of comparing two specs
+type DiffResult struct {
+	AddedPaths        []string          `json:"addedPaths"`
+	RemovedPaths      []string          `json:"removedPaths"`
+	ChangedOperations []OperationChange `json:"changedOperations"`
+	BreakingChanges   []BreakingChange  `json:"breakingChanges"`
+}
+
+// An operation that was added, removed or modified between two specs
+type OperationChange struct {
+	Path    string   `json:"path"`
+	Method  string   `json:"method"`
+	Change  string   `json:"change"`
+	Details []string `json:"details,omitempty"`
+}
+
+// A change that can break existing clients of the API
+type BreakingChange struct {
+	Path        string `json:"path"`
+	Method      string `json:"method,omitempty"`
+	Description string `json:"description"`
+}
+
+// A single difference found while comparing two parts of a spec
+type specChange struct {
+	description string
+	breaking    bool
+}
+
+// Compare two specs and report what changed going from a to b
+func DiffSpecs(a, b *SwaggerTemplate) DiffResult {
+	result := DiffResult{
+		AddedPaths:        []string{},
+		RemovedPaths:      []string{},
+		ChangedOperations: []OperationChange{},
+		BreakingChanges:   []BreakingChange{},
+	}
+
+	for _, path := range unionKeys(a.Paths, b.Paths) {
+		oldItem, inOld := a.Paths[path]
+		newItem, inNew := b.Paths[path]
+		switch {
+		case !inOld:
+			result.AddedPaths = append(result.AddedPaths, path)
+		case !inNew:
+			result.RemovedPaths = append(result.RemovedPaths, path)
+			result.BreakingChanges = append(result.BreakingChanges, BreakingChange{
+				Path:        path,
+				Description: "path removed",
+			})
+		default:
+			diffPath(&result, path, oldItem, newItem)
+		}
+	}
+
+	return result
+}
+
+// Compare the operations of a path present in both specs. Parameters are
+// compared as they apply to each operation, so moving one between the
+// operation and the path isn't a change.
+func diffPath(result *DiffResult, path string, oldItem, newItem PathItem) {
+	for _, method := range unionKeys(oldItem.Operations, newItem.Operations) {
+		oldOperation, inOld := oldItem.Operations[method]
+		newOperation, inNew := newItem.Operations[method]
+		oldOperation.Parameters = effectiveParameters(oldItem, oldOperation)
+		newOperation.Parameters = effectiveParameters(newItem, newOperation)
+		switch {
+		case !inOld:
+			result.ChangedOperations = append(result.ChangedOperations, OperationChange{Path: path, Method: method, Change: "added"})
+		case !inNew:
+			result.ChangedOperations = append(result.ChangedOperations, OperationChange{Path: path, Method: method, Change: "removed"})
+			result.BreakingChanges = append(result.BreakingChanges, BreakingChange{
+				Path:        path,
+				Method:      method,
+				Description: "operation removed",
+			})
+		default:
+			changes := diffOperations(oldOperation, newOperation)
+			if len(changes) == 0 {
+				continue
+			}
+			change := OperationChange{Path: path, Method: method, Change: "modified"}
+			for _, c := range changes {
+				change.Details = append(change.Details, c.description)
+				if c.breaking {
+					result.BreakingChanges = append(result.BreakingChanges, BreakingChange{
+						Path:        path,
+						Method:      method,
+						Description: c.description,
+					})
+				}
+			}
+			result.ChangedOperations = append(result.ChangedOperations, change)
+		}
+	}
+}
+
+// Compare two versions of the same operation
+func diffOperations(a, b Operation) []specChange {
+	var changes []specChange
+	if a.Summary != b.Summary {
+		changes = append(changes, specChange{description: "summary changed"})
+	}
+	if a.Description != b.Description {
+		changes = append(changes, specChange{description: "description changed"})
+	}
+
+	oldParams := make(map[string]Parameter)
+	for _, param := range a.Parameters {
+		oldParams[param.In+":"+param.Name] = param
+	}
+	newParams := make(map[string]Parameter)
+	for _, param := range b.Parameters {
+		newParams[param.In+":"+param.Name] = param
+		oldParam, existed := oldParams[param.In+":"+param.Name]
+		switch {
+		case !existed:
+			changes = append(changes, specChange{
+				description: fmt.Sprintf("%s parameter %q added", param.In, param.Name),
+				breaking:    param.Required,
+			})
+		case param.Required && !oldParam.Required:
+			changes = append(changes, specChange{
+				description: fmt.Sprintf("%s parameter %q became required", param.In, param.Name),
+				breaking:    true,
+			})
+		}
+		if existed && oldParam.Schema != nil && param.Schema != nil {
+			changes = append(changes, diffSchemas(*oldParam.Schema, *param.Schema, fmt.Sprintf("%s parameter %q", param.In, param.Name))...)
+		}
+	}
+	for _, param := range a.Parameters {
+		if _, kept := newParams[param.In+":"+param.Name]; !kept {
+			changes = append(changes, specChange{description: fmt.Sprintf("%s parameter %q removed", param.In, param.Name)})
+		}
+	}
+
+	for _, status := range unionKeys(a.Responses, b.Responses) {
+		oldResponse, inOld := a.Responses[status]
+		newResponse, inNew := b.Responses[status]
+		switch {
+		case !inOld:
+			changes = append(changes, specChange{description: fmt.Sprintf("response %s added", status)})
+		case !inNew:
+			changes = append(changes, specChange{description: fmt.Sprintf("response %s removed", status), breaking: true})
+		default:
+			changes = append(changes, diffResponses(oldResponse, newResponse, "response "+status)...)
+		}
+	}
+	return changes
+}
+
+// Compare two versions of the same response
+func diffResponses(a, b Response, location string) []specChange {
+	var changes []specChange
+	for _, mediaType := range unionKeys(a.Content, b.Content) {
+		oldMedia, inOld := a.Content[mediaType]
+		newMedia, inNew := b.Content[mediaType]
+		switch {
+		case !inOld:
+			changes = append(changes, specChange{description: fmt.Sprintf("%s: content type %s added", location, mediaType)})
+		case !inNew:
+			changes = append(changes, specChange{description: fmt.Sprintf("%s: content type %s removed", location, mediaType), breaking: true})
+		default:
+			changes = append(changes, diffSchemas(oldMedia.Schema, newMedia.Schema, location+" "+mediaType)...)
+		}
+	}
+	return changes
+}
+
+// Compare two versions of a schema, recursing into properties and items.
+// Removed properties and changed types break clients reading the data.
+func diffSchemas(a, b Schema, location string) []specChange {
+	if a.Ref != b.Ref {
+		return []specChange{{
+			description: fmt.Sprintf("%s: reference changed from %q to %q", location, a.Ref, b.Ref),
+			breaking:    true,
+		}}
+	}
+	if a.Type != b.Type {
+		return []specChange{{
+			description: fmt.Sprintf("%s: type changed from %s to %s", location, a.Type, b.Type),
+			breaking:    true,
+		}}
+	}
+
+	var changes []specChange
+	if a.Format != b.Format {
+		changes = append(changes, specChange{
+			description: fmt.Sprintf("%s: format changed from %q to %q", location, a.Format, b.Format),
+			breaking:    a.Format != "",
+		})
+	}
+	for _, name := range unionKeys(a.Properties, b.Properties) {
+		oldProp, inOld := a.Properties[name]
+		newProp, inNew := b.Properties[name]
+		propLocation := joinKeyPath(location, name)
+		switch {
+		case !inOld:
+			changes = append(changes, specChange{description: propLocation + ": property added"})
+		case !inNew:
+			changes = append(changes, specChange{description: propLocation + ": property removed", breaking: true})
+		default:
+			changes = append(changes, diffSchemas(oldProp, newProp, propLocation)...)
+		}
+	}
+	if a.Items != nil && b.Items != nil {
+		changes = append(changes, diffSchemas(*a.Items, *b.Items, location+"[]")...)
+	}
+	return changes
+}
+
+// Print the differences between two Swagger YAML files
+func diffSwagger(oldPath, newPath string) error {
+	oldSwagger, err := readSwaggerFile(oldPath)
+	if err != nil {
+		return err
+	}
+	newSwagger, err := readSwaggerFile(newPath)
+	if err != nil {
+		return err
+	}
+
+	result := DiffSpecs(oldSwagger, newSwagger)
+	if *diffJSON {
+		data, err := json.MarshalIndent(result, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	fmt.Print(formatDiff(result))
+	return nil
+}
+
+// Format a diff result as a human-readable summary
+func formatDiff(result DiffResult) string {
+	var sb strings.Builder
+	if len(result.AddedPaths)+len(result.RemovedPaths)+len(result.ChangedOperations) == 0 {
+		sb.WriteString("No differences found.\n")
+		return sb.String()
+	}
+
+	for _, path := range result.AddedPaths {
+		fmt.Fprintf(&sb, "+ %s\n", path)
+	}
+	for _, path := range result.RemovedPaths {
+		fmt.Fprintf(&sb, "- %s\n", path)
+	}
+	for _, change := range result.ChangedOperations {
+		marker := map[string]string{"added": "+", "removed": "-", "modified": "~"}[change.Change]
+		fmt.Fprintf(&sb, "%s %s %s\n", marker, strings.ToUpper(change.Method), change.Path)
+		for _, detail := range change.Details {
+			fmt.Fprintf(&sb, "    %s\n", detail)
+		}
+	}
+
+	if len(result.BreakingChanges) > 0 {
+		fmt.Fprintf(&sb, "\nBreaking changes (%d):\n", len(result.BreakingChanges))
+		for _, change := range result.BreakingChanges {
+			if change.Method != "" {
+				fmt.Fprintf(&sb, "  %s %s: %s\n", strings.ToUpper(change.Method), change.Path, change.Description)
+			} else {
+				fmt.Fprintf(&sb, "  %s: %s\n", change.Path, change.Description)
+			}
+		}
+	}
+	return sb.String()
+}
+
+// Sorted union of the keys of two maps
+func unionKeys[V any](a, b map[string]V) []string {
+	seen := make(map[string]bool)
+	for key := range a {
+		seen[key] = true
+	}
+	for key := range b {
+		seen[key] = true
+	}
+	return sortedKeys(seen)
+}
+
+// Keys of a map in sorted order
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
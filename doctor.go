@@ -0,0 +1,74 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+	"runtime/debug"
+)
+
+// Print a diagnostic report on the environment and a spec file, for bug
+// reports. Only an unreadable file is an error; other findings are
+// reported and the checks carry on.
+func doctor(filePath string) error {
+	fmt.Printf("go: %s %s/%s\n", runtime.Version(), runtime.GOOS, runtime.GOARCH)
+	fmt.Printf("yaml library: %s\n", moduleVersion("gopkg.in/yaml.v2"))
+
+	fmt.Printf("file: %s\n", filePath)
+	f, err := os.Open(filePath)
+	if err != nil {
+		fmt.Println("  readable: no")
+		return fmt.Errorf("%s can't be read: %w", filePath, err)
+	}
+	f.Close()
+	fmt.Println("  readable: yes")
+
+	if f, err := os.OpenFile(filePath, os.O_WRONLY, 0); err != nil {
+		fmt.Printf("  writable: no (%v)\n", err)
+	} else {
+		f.Close()
+		fmt.Println("  writable: yes")
+	}
+	if _, err := os.Stat(filePath + ".lock"); err == nil {
+		fmt.Printf("  lock: %s.lock exists; remove it if no other process is editing the file\n", filePath)
+	}
+
+	swagger, err := readSwaggerFile(filePath)
+	if err != nil {
+		fmt.Printf("  parses: no (%v)\n", err)
+		return nil
+	}
+	fmt.Println("  parses: yes")
+
+	version := swagger.OpenAPI
+	if version == "" {
+		version = "missing"
+	}
+	operations := 0
+	for _, item := range swagger.Paths {
+		operations += len(item.Operations)
+	}
+	fmt.Printf("  openapi version: %s\n", version)
+	fmt.Printf("  paths: %d (%d operations)\n", len(swagger.Paths), operations)
+	fmt.Printf("  component schemas: %d\n", len(swagger.Components.Schemas))
+	fmt.Printf("  validation problems: %d\n", len(validateSwagger(swagger)))
+	return nil
+}
+
+// The version of a module dependency built into the binary, or "unknown"
+// when build information isn't available
+func moduleVersion(path string) string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return "unknown"
+	}
+	for _, dep := range info.Deps {
+		if dep.Path == path {
+			if dep.Replace != nil {
+				return dep.Version + " => " + dep.Replace.Path + " " + dep.Replace.Version
+			}
+			return dep.Version
+		}
+	}
+	return "unknown"
+}
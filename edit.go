@@ -0,0 +1,132 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+var parameterLocations = []string{"query", "path", "header", "cookie"}
+
+// Edit one operation through a menu of changes, showing its current state
+// before each choice. All changes are written at once on save; on discard,
+// or if input ends before saving, the file is left untouched.
+func editOperation(filePath, path, method string, reader *bufio.Reader) error {
+	unlock, err := lockFile(filePath)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	swagger, err := readSwaggerFile(filePath)
+	if err != nil {
+		return err
+	}
+	operation, ok := swagger.Paths[path].Operations[method]
+	if !ok {
+		return fmt.Errorf("operation %s %s not found", strings.ToUpper(method), path)
+	}
+
+	for {
+		// The operation is shown on stderr with the prompts, so that a spec
+		// saved to stdout isn't mixed with it
+		fmt.Fprint(os.Stderr, "\n"+formatOperation(path, method, operation))
+		line, err := promptLine(reader, "\nEnter change (summary/add-param/remove-param/add-response/remove-response/deprecated/save/discard): ")
+		if err == io.EOF {
+			return fmt.Errorf("input ended before saving, changes discarded")
+		}
+
+		switch choice := strings.ToLower(line); choice {
+		case "summary":
+			operation.Summary = prompt(reader, "Enter the new summary: ")
+		case "add-param":
+			param, err := promptParameter(reader)
+			if err != nil {
+				fmt.Println("Error:", err)
+				continue
+			}
+			operation.Parameters = append(operation.Parameters, param)
+		case "remove-param":
+			name := prompt(reader, "Enter the parameter name: ")
+			if !removeParameter(&operation, name) {
+				fmt.Printf("No parameter named %s.\n", name)
+			}
+		case "add-response":
+			status := prompt(reader, "Enter the status code: ")
+			if !statusCodePattern.MatchString(status) {
+				fmt.Printf("Invalid status code %q.\n", status)
+				continue
+			}
+			if _, exists := operation.Responses[status]; exists {
+				fmt.Printf("A %s response already exists; remove it first to replace it.\n", status)
+				continue
+			}
+			description := prompt(reader, fmt.Sprintf("Enter the description (blank for %s): ", responseDescription(status)))
+			if description == "" {
+				description = responseDescription(status)
+			}
+			if operation.Responses == nil {
+				operation.Responses = make(map[string]Response)
+			}
+			operation.Responses[status] = Response{Description: description}
+		case "remove-response":
+			status := prompt(reader, "Enter the status code: ")
+			if _, exists := operation.Responses[status]; !exists {
+				fmt.Printf("No %s response.\n", status)
+				continue
+			}
+			delete(operation.Responses, status)
+		case "deprecated":
+			operation.Deprecated = strings.EqualFold(prompt(reader, "Mark as deprecated? (y/n): "), "y")
+		case "save":
+			swagger.Paths[path].Operations[method] = operation
+			return writeSwaggerFile(outputFile(filePath), swagger)
+		case "discard":
+			fmt.Println("Changes discarded.")
+			return nil
+		case "":
+		default:
+			fmt.Printf("Unknown change %q.\n", choice)
+		}
+	}
+}
+
+// Prompt for a new parameter's name, location, whether it's required and
+// its type. Path parameters are always required.
+func promptParameter(reader *bufio.Reader) (Parameter, error) {
+	name := prompt(reader, "Enter the parameter name: ")
+	if name == "" {
+		return Parameter{}, fmt.Errorf("parameter name is required")
+	}
+	in := strings.ToLower(prompt(reader, fmt.Sprintf("Enter the location (%s): ", strings.Join(parameterLocations, "/"))))
+	if !containsString(parameterLocations, in) {
+		return Parameter{}, fmt.Errorf("invalid location %q", in)
+	}
+
+	required := in == "path"
+	if !required {
+		required = strings.EqualFold(prompt(reader, "Required? (y/n): "), "y")
+	}
+	return Parameter{
+		Name:     name,
+		In:       in,
+		Required: required,
+		Schema:   &Schema{Type: promptSchemaType(reader, name)},
+	}, nil
+}
+
+// Remove the parameters with a name from an operation, reporting whether
+// there were any
+func removeParameter(operation *Operation, name string) bool {
+	kept := operation.Parameters[:0]
+	for _, param := range operation.Parameters {
+		if param.Name != name {
+			kept = append(kept, param)
+		}
+	}
+	removed := len(kept) < len(operation.Parameters)
+	operation.Parameters = kept
+	return removed
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"strings"
+)
+
+// Media types whose properties can have an encoding
+var formMediaTypes = []string{"multipart/form-data", "application/x-www-form-urlencoded"}
+
+// Serialization styles allowed in an encoding
+var encodingStyles = []string{"form", "spaceDelimited", "pipeDelimited", "deepObject"}
+
+// Set the encoding of one property of an operation's form request body,
+// such as the content type of a multipart part holding JSON
+func addEncoding(filePath, path, method string, reader *bufio.Reader) error {
+	unlock, err := lockFile(filePath)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	swagger, err := readSwaggerFile(filePath)
+	if err != nil {
+		return err
+	}
+	operation, ok := swagger.Paths[path].Operations[method]
+	if !ok {
+		return fmt.Errorf("operation %s %s not found", strings.ToUpper(method), path)
+	}
+	if operation.RequestBody == nil {
+		return fmt.Errorf("operation %s %s has no request body", strings.ToUpper(method), path)
+	}
+
+	var mediaType string
+	for _, candidate := range formMediaTypes {
+		if _, ok := operation.RequestBody.Content[candidate]; ok {
+			mediaType = candidate
+			break
+		}
+	}
+	if mediaType == "" {
+		return fmt.Errorf("operation %s %s has no %s request body", strings.ToUpper(method), path, strings.Join(formMediaTypes, " or "))
+	}
+	media := operation.RequestBody.Content[mediaType]
+
+	property := prompt(reader, "Enter the property to encode: ")
+	if _, ok := media.Schema.Properties[property]; !ok {
+		return fmt.Errorf("the %s request body has no property %q", mediaType, property)
+	}
+	encoding := media.Encoding[property]
+	if contentType := prompt(reader, "Enter the content type (e.g., application/json, blank to keep): "); contentType != "" {
+		encoding.ContentType = contentType
+	}
+	if mediaType == "application/x-www-form-urlencoded" {
+		style := prompt(reader, fmt.Sprintf("Enter the style (%s, blank to keep): ", strings.Join(encodingStyles, "/")))
+		if style != "" && !containsString(encodingStyles, style) {
+			return fmt.Errorf("invalid style %q", style)
+		}
+		if style != "" {
+			encoding.Style = style
+		}
+	}
+
+	if media.Encoding == nil {
+		media.Encoding = make(map[string]Encoding)
+	}
+	media.Encoding[property] = encoding
+	operation.RequestBody.Content[mediaType] = media
+	swagger.Paths[path].Operations[method] = operation
+	return writeSwaggerFile(outputFile(filePath), swagger)
+}
+
+// Check the encodings of a media type: they only apply to form bodies and
+// must name properties of the schema
+func validateEncoding(mediaType string, media MediaType, location string) []string {
+	var problems []string
+	for _, property := range sortedKeys(media.Encoding) {
+		propertyLocation := location + ".encoding." + property
+		if !containsString(formMediaTypes, mediaType) {
+			problems = append(problems, propertyLocation+": encoding only applies to multipart and urlencoded bodies")
+			continue
+		}
+		if _, ok := media.Schema.Properties[property]; !ok && media.Schema.Ref == "" {
+			problems = append(problems, propertyLocation+": property not in the schema")
+		}
+		if style := media.Encoding[property].Style; style != "" && !containsString(encodingStyles, style) {
+			problems = append(problems, fmt.Sprintf("%s: invalid style %q", propertyLocation, style))
+		}
+	}
+	return problems
+}
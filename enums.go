@@ -0,0 +1,82 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// An enum found in a spec, and where
+type enumUse struct {
+	location string
+	values   []interface{}
+}
+
+// Print the location and values of every enum in a spec, then the value sets
+// used more than once, which are candidates for a shared component schema
+func listEnums(filePath string) error {
+	swagger, err := readSwaggerFile(filePath)
+	if err != nil {
+		return err
+	}
+
+	var uses []enumUse
+	walkSpecSchemas(swagger, func(schema *Schema, location string) {
+		if len(schema.Enum) > 0 {
+			uses = append(uses, enumUse{location: location, values: schema.Enum})
+		}
+	})
+	if len(uses) == 0 {
+		fmt.Println("No enums found.")
+		return nil
+	}
+
+	// Identical value sets are grouped regardless of the order the values
+	// are listed in
+	groups := make(map[string][]string)
+	for _, use := range uses {
+		values := formatEnumValues(use.values)
+		fmt.Printf("%s: %s\n", use.location, strings.Join(values, ", "))
+
+		sort.Strings(values)
+		key := strings.Join(values, ", ")
+		groups[key] = append(groups[key], use.location)
+	}
+
+	shared := 0
+	for _, key := range sortedKeys(groups) {
+		if len(groups[key]) < 2 {
+			continue
+		}
+		if shared == 0 {
+			fmt.Println("\nValue sets used in more than one place, consider extracting them into a shared component schema:")
+		}
+		shared++
+		fmt.Printf("  [%s] in %d places:\n", key, len(groups[key]))
+		for _, location := range groups[key] {
+			fmt.Println("    " + location)
+		}
+	}
+	return nil
+}
+
+// Format enum values as JSON literals, so strings and numbers that print
+// alike stay distinct
+func formatEnumValues(values []interface{}) []string {
+	formatted := make([]string, 0, len(values))
+	for _, value := range values {
+		normalized, err := normalizeYAMLValue(value)
+		if err != nil {
+			formatted = append(formatted, fmt.Sprint(value))
+			continue
+		}
+		literal, err := json.Marshal(normalized)
+		if err != nil {
+			formatted = append(formatted, fmt.Sprint(value))
+			continue
+		}
+		formatted = append(formatted, string(literal))
+	}
+	return formatted
+}
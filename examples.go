@@ -0,0 +1,80 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Check the example of every request and response media type against its
+// schema, with the same checks as test-response
+func checkExamples(filePath string) error {
+	swagger, err := readSwaggerFile(filePath)
+	if err != nil {
+		return err
+	}
+
+	issues := exampleMismatches(swagger)
+	for _, issue := range issues {
+		fmt.Printf("  %s: %s\n", issue.location, issue.message)
+	}
+	if len(issues) > 0 {
+		return fmt.Errorf("%s has %d example mismatches", filePath, len(issues))
+	}
+	fmt.Println("All examples match their schemas.")
+	return nil
+}
+
+// Find the media type examples that don't match their schemas, as lint
+// errors located at the example
+func exampleMismatches(swagger *SwaggerTemplate) []lintIssue {
+	var issues []lintIssue
+	for _, path := range sortedKeys(swagger.Paths) {
+		for _, method := range sortedKeys(swagger.Paths[path].Operations) {
+			operation := swagger.Paths[path].Operations[method]
+			location := fmt.Sprintf("paths.%s.%s", path, method)
+			if operation.RequestBody != nil {
+				issues = append(issues, contentExampleMismatches(swagger, operation.RequestBody.Content, location+".requestBody")...)
+			}
+			for _, status := range sortedKeys(operation.Responses) {
+				issues = append(issues, contentExampleMismatches(swagger, operation.Responses[status].Content, fmt.Sprintf("%s.responses.%s", location, status))...)
+			}
+		}
+	}
+	return issues
+}
+
+// Check the examples of each media type in a content map. An example is
+// round-tripped through JSON so that it has the shape test-response
+// samples have.
+func contentExampleMismatches(swagger *SwaggerTemplate, content map[string]MediaType, location string) []lintIssue {
+	var issues []lintIssue
+	for _, mediaType := range sortedKeys(content) {
+		media := content[mediaType]
+		if media.Example == nil {
+			continue
+		}
+		exampleLocation := fmt.Sprintf("%s.content.%s.example", location, mediaType)
+
+		normalized, err := normalizeYAMLValue(media.Example)
+		if err != nil {
+			issues = append(issues, lintIssue{"error", exampleLocation, err.Error()})
+			continue
+		}
+		data, err := json.Marshal(normalized)
+		if err != nil {
+			issues = append(issues, lintIssue{"error", exampleLocation, err.Error()})
+			continue
+		}
+		var example interface{}
+		if err := decodeJSON(data, &example); err != nil {
+			issues = append(issues, lintIssue{"error", exampleLocation, err.Error()})
+			continue
+		}
+
+		for _, mismatch := range sampleMismatches(swagger, media.Schema, example, "", nil) {
+			issues = append(issues, lintIssue{"error", exampleLocation, strings.TrimPrefix(mismatch, "(root): ")})
+		}
+	}
+	return issues
+}
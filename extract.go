@@ -0,0 +1,149 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// Write a new spec holding only the operations tagged with any of tags,
+// along with the component schemas they reference, directly or through
+// other components. Everything else in components is left out, and the
+// top-level tag list keeps only the tags the extracted operations use.
+func extractByTags(filePath string, tags []string, target string) error {
+	swagger, err := readSwaggerFile(filePath)
+	if err != nil {
+		return err
+	}
+
+	extracted := &SwaggerTemplate{
+		OpenAPI:    swagger.OpenAPI,
+		Info:       swagger.Info,
+		Servers:    swagger.Servers,
+		Paths:      make(map[string]PathItem),
+		Extensions: swagger.Extensions,
+	}
+	count := 0
+	usedTags := make(map[string]bool)
+	for _, path := range sortedKeys(swagger.Paths) {
+		for _, method := range sortedKeys(swagger.Paths[path].Operations) {
+			operation := swagger.Paths[path].Operations[method]
+			if !hasAnyTag(operation, tags) {
+				continue
+			}
+			ensurePathItem(extracted, path)
+			item := extracted.Paths[path]
+			item.Parameters = swagger.Paths[path].Parameters
+			item.Operations[method] = operation
+			extracted.Paths[path] = item
+			for _, tag := range operation.Tags {
+				usedTags[tag] = true
+			}
+			count++
+		}
+	}
+	if count == 0 {
+		return fmt.Errorf("no operations are tagged %s", strings.Join(tags, " or "))
+	}
+
+	refs, err := componentRefs(extracted.Paths)
+	if err != nil {
+		return err
+	}
+	if extracted.Components.Schemas, err = referencedComponents(swagger, refs); err != nil {
+		return err
+	}
+
+	if list, ok := swagger.Extensions["tags"].([]interface{}); ok {
+		extracted.Extensions = make(map[string]interface{}, len(swagger.Extensions))
+		for key, value := range swagger.Extensions {
+			extracted.Extensions[key] = value
+		}
+		var kept []interface{}
+		for _, tag := range list {
+			if definition, ok := tag.(map[interface{}]interface{}); ok && usedTags[fmt.Sprint(definition["name"])] {
+				kept = append(kept, tag)
+			}
+		}
+		extracted.Extensions["tags"] = kept
+		if len(kept) == 0 {
+			delete(extracted.Extensions, "tags")
+		}
+	}
+
+	if err := writeSwaggerFile(target, extracted); err != nil {
+		return err
+	}
+	fmt.Printf("Extracted %d operations and %d of %d component schemas.\n", count, len(extracted.Components.Schemas), len(swagger.Components.Schemas))
+	return nil
+}
+
+// Report whether an operation has at least one of tags
+func hasAnyTag(operation Operation, tags []string) bool {
+	for _, tag := range operation.Tags {
+		if containsString(tags, tag) {
+			return true
+		}
+	}
+	return false
+}
+
+// The component schemas named in refs, along with the ones they refer to
+// in turn, or nil when there are none. Names missing from the spec are
+// skipped.
+func referencedComponents(swagger *SwaggerTemplate, refs []string) (map[string]Schema, error) {
+	var components map[string]Schema
+	for len(refs) > 0 {
+		name := refs[0]
+		refs = refs[1:]
+		schema, ok := swagger.Components.Schemas[name]
+		if _, done := components[name]; done || !ok {
+			continue
+		}
+		if components == nil {
+			components = make(map[string]Schema)
+		}
+		components[name] = schema
+		more, err := componentRefs(schema)
+		if err != nil {
+			return nil, err
+		}
+		refs = append(refs, more...)
+	}
+	return components, nil
+}
+
+// The names of the component schemas a part of a spec refers to. It is
+// marshalled and read back generically so that refs in callbacks and other
+// parts kept as plain maps are found as well as those in schemas.
+func componentRefs(part interface{}) ([]string, error) {
+	data, err := yaml.Marshal(part)
+	if err != nil {
+		return nil, err
+	}
+	var document interface{}
+	if err := yaml.Unmarshal(data, &document); err != nil {
+		return nil, err
+	}
+
+	var names []string
+	var visit func(value interface{})
+	visit = func(value interface{}) {
+		switch v := value.(type) {
+		case map[interface{}]interface{}:
+			if ref, ok := v["$ref"].(string); ok && strings.HasPrefix(ref, schemaRefPrefix) {
+				names = append(names, strings.NewReplacer("~1", "/", "~0", "~").Replace(strings.TrimPrefix(ref, schemaRefPrefix)))
+			}
+			for _, item := range v {
+				visit(item)
+			}
+		case []interface{}:
+			for _, item := range v {
+				visit(item)
+			}
+		}
+	}
+	visit(document)
+	return names, nil
+}
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+// List the paths matching a glob, e.g. /pets/*, with their methods. Matching
+// follows path.Match, so * doesn't cross a / and {params} match literally.
+func findPaths(filePath, pattern string) error {
+	if _, err := path.Match(pattern, ""); err != nil {
+		return fmt.Errorf("invalid pattern %q: %w", pattern, err)
+	}
+
+	swagger, err := readSwaggerFile(filePath)
+	if err != nil {
+		return err
+	}
+
+	found := 0
+	for _, p := range sortedKeys(swagger.Paths) {
+		if matched, _ := path.Match(pattern, p); !matched {
+			continue
+		}
+		var methods []string
+		for _, method := range sortedKeys(swagger.Paths[p].Operations) {
+			methods = append(methods, strings.ToUpper(method))
+		}
+		fmt.Printf("%s %s\n", p, strings.Join(methods, ", "))
+		found++
+	}
+
+	if found == 0 {
+		fmt.Printf("No paths match %s.\n", pattern)
+	}
+	return nil
+}
@@ -0,0 +1,181 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"strings"
+)
+
+// The subset of a GraphQL introspection result needed to describe types
+type graphQLIntrospection struct {
+	Data   *graphQLIntrospection `json:"data"`
+	Schema *struct {
+		QueryType        *graphQLNamedType `json:"queryType"`
+		MutationType     *graphQLNamedType `json:"mutationType"`
+		SubscriptionType *graphQLNamedType `json:"subscriptionType"`
+		Types            []graphQLType     `json:"types"`
+	} `json:"__schema"`
+}
+
+type graphQLNamedType struct {
+	Name string `json:"name"`
+}
+
+type graphQLType struct {
+	Kind        string         `json:"kind"`
+	Name        string         `json:"name"`
+	Description string         `json:"description"`
+	Fields      []graphQLField `json:"fields"`
+	InputFields []graphQLField `json:"inputFields"`
+	EnumValues  []struct {
+		Name string `json:"name"`
+	} `json:"enumValues"`
+}
+
+type graphQLField struct {
+	Name        string         `json:"name"`
+	Description string         `json:"description"`
+	Type        graphQLTypeRef `json:"type"`
+}
+
+// A possibly wrapped reference to a type, e.g. [String!]! is
+// NON_NULL of LIST of NON_NULL of SCALAR String
+type graphQLTypeRef struct {
+	Kind   string          `json:"kind"`
+	Name   string          `json:"name"`
+	OfType *graphQLTypeRef `json:"ofType"`
+}
+
+// Built-in GraphQL scalars and the schemas they map to
+var graphQLScalars = map[string]Schema{
+	"String":  {Type: "string"},
+	"ID":      {Type: "string"},
+	"Int":     {Type: "integer", Format: "int32"},
+	"Float":   {Type: "number"},
+	"Boolean": {Type: "boolean"},
+}
+
+// Add a component schema to a spec for each object, input object and enum
+// type in a GraphQL introspection result. Only components are generated;
+// GraphQL operations don't map onto REST paths.
+func importGraphQL(filePath, introspectionPath string) error {
+	unlock, err := lockFile(filePath)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	swagger, err := readSwaggerFile(filePath)
+	if err != nil {
+		return err
+	}
+
+	data, err := ioutil.ReadFile(introspectionPath)
+	if err != nil {
+		return err
+	}
+	var result graphQLIntrospection
+	if err := json.Unmarshal(data, &result); err != nil {
+		return err
+	}
+	// Accept both the raw response and just its data member
+	if result.Data != nil {
+		result = *result.Data
+	}
+	if result.Schema == nil {
+		return fmt.Errorf("%s is not a GraphQL introspection result: __schema not found", introspectionPath)
+	}
+
+	rootTypes := make(map[string]bool)
+	for _, root := range []*graphQLNamedType{result.Schema.QueryType, result.Schema.MutationType, result.Schema.SubscriptionType} {
+		if root != nil {
+			rootTypes[root.Name] = true
+		}
+	}
+
+	if swagger.Components.Schemas == nil {
+		swagger.Components.Schemas = make(map[string]Schema)
+	}
+	var warnings []string
+	imported := 0
+	for _, graphQLType := range result.Schema.Types {
+		if strings.HasPrefix(graphQLType.Name, "__") || rootTypes[graphQLType.Name] {
+			continue
+		}
+
+		switch graphQLType.Kind {
+		case "OBJECT", "INPUT_OBJECT":
+			schema, typeWarnings := graphQLObjectSchema(graphQLType)
+			warnings = append(warnings, typeWarnings...)
+			swagger.Components.Schemas[graphQLType.Name] = schema
+			imported++
+		case "ENUM":
+			schema := Schema{Type: "string"}
+			for _, value := range graphQLType.EnumValues {
+				schema.Enum = append(schema.Enum, value.Name)
+			}
+			swagger.Components.Schemas[graphQLType.Name] = schema
+			imported++
+		case "UNION", "INTERFACE":
+			warnings = append(warnings, fmt.Sprintf("%s: %s types are not supported and were skipped", graphQLType.Name, strings.ToLower(graphQLType.Kind)))
+		}
+	}
+
+	fmt.Printf("Imported %d GraphQL types as component schemas.\n", imported)
+	for _, warning := range warnings {
+		fmt.Println("Warning:", warning)
+	}
+	return writeSwaggerFile(outputFile(filePath), swagger)
+}
+
+// Build the schema of an object or input object type. Non-null fields
+// become required properties.
+func graphQLObjectSchema(graphQLType graphQLType) (Schema, []string) {
+	fields := graphQLType.Fields
+	if graphQLType.Kind == "INPUT_OBJECT" {
+		fields = graphQLType.InputFields
+	}
+
+	schema := Schema{Type: "object", Properties: make(map[string]Schema)}
+	var warnings []string
+	for _, field := range fields {
+		propSchema, required, err := graphQLFieldSchema(field.Type)
+		if err != nil {
+			warnings = append(warnings, fmt.Sprintf("%s.%s: %v", graphQLType.Name, field.Name, err))
+		}
+		schema.Properties[field.Name] = propSchema
+		if required {
+			schema.Required = append(schema.Required, field.Name)
+		}
+	}
+	return schema, warnings
+}
+
+// Map a field's type reference to a schema, reporting whether it is
+// non-null. Unsupported types map to an unconstrained schema and an error.
+func graphQLFieldSchema(ref graphQLTypeRef) (Schema, bool, error) {
+	required := false
+	if ref.Kind == "NON_NULL" && ref.OfType != nil {
+		required = true
+		ref = *ref.OfType
+	}
+
+	switch ref.Kind {
+	case "LIST":
+		if ref.OfType == nil {
+			return Schema{Type: "array", Items: &Schema{}}, required, nil
+		}
+		items, _, err := graphQLFieldSchema(*ref.OfType)
+		return Schema{Type: "array", Items: &items}, required, err
+	case "SCALAR":
+		if schema, ok := graphQLScalars[ref.Name]; ok {
+			return schema, required, nil
+		}
+		return Schema{Type: "string"}, required, fmt.Errorf("custom scalar %s mapped to string", ref.Name)
+	case "OBJECT", "INPUT_OBJECT", "ENUM":
+		return Schema{Ref: schemaRefPrefix + ref.Name}, required, nil
+	default:
+		return Schema{}, required, fmt.Errorf("%s %s is not supported", strings.ToLower(ref.Kind), ref.Name)
+	}
+}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"sort"
+
+	"gopkg.in/yaml.v2"
+	yamlv3 "gopkg.in/yaml.v3"
+)
+
+var groupByTag = flag.Bool("group-by-tag", false, "write paths grouped by the first tag of their operations, with a comment naming each group in YAML output; paths without tags go last")
+
+// A run of paths sharing a primary tag, "" for untagged paths
+type tagGroup struct {
+	tag   string
+	paths []string
+}
+
+// The paths of a spec grouped by primary tag: the first tag of the first
+// tagged operation in method order. Groups are sorted by tag with untagged
+// paths last, and paths sorted within each group.
+func tagGroups(swagger *SwaggerTemplate) []tagGroup {
+	byTag := make(map[string][]string)
+	for _, path := range sortedKeys(swagger.Paths) {
+		tag := ""
+		for _, method := range sortedKeys(swagger.Paths[path].Operations) {
+			if tags := swagger.Paths[path].Operations[method].Tags; len(tags) > 0 {
+				tag = tags[0]
+				break
+			}
+		}
+		byTag[tag] = append(byTag[tag], path)
+	}
+
+	tags := sortedKeys(byTag)
+	sort.SliceStable(tags, func(i, j int) bool {
+		return tags[i] != "" && tags[j] == ""
+	})
+	groups := make([]tagGroup, 0, len(tags))
+	for _, tag := range tags {
+		groups = append(groups, tagGroup{tag: tag, paths: byTag[tag]})
+	}
+	return groups
+}
+
+// Reorder the paths entry of a marshalled spec to follow the tag groups.
+// Only the order changes, not the content.
+func orderPathsByTag(document yaml.MapSlice, groups []tagGroup) {
+	for i, item := range document {
+		if item.Key != "paths" {
+			continue
+		}
+		paths, ok := item.Value.(yaml.MapSlice)
+		if !ok {
+			return
+		}
+		byPath := make(map[interface{}]yaml.MapItem, len(paths))
+		for _, path := range paths {
+			byPath[path.Key] = path
+		}
+		ordered := make(yaml.MapSlice, 0, len(paths))
+		for _, group := range groups {
+			for _, path := range group.paths {
+				ordered = append(ordered, byPath[path])
+			}
+		}
+		document[i].Value = ordered
+	}
+}
+
+// Add a comment naming each tag group before its first path in YAML
+// written with orderPathsByTag. yaml.v2 can't write comments, so the YAML
+// is read back as yaml.v3 nodes, the comments are set as head comments of
+// the path keys, and yaml.v3 writes the document again. Its output differs
+// from yaml.v2's only in style: lists are indented under their key and
+// long strings aren't wrapped.
+func commentTagGroups(data []byte, groups []tagGroup) ([]byte, error) {
+	headers := make(map[string]string, len(groups))
+	for _, group := range groups {
+		tag := group.tag
+		if tag == "" {
+			tag = "(untagged)"
+		}
+		if len(group.paths) > 0 {
+			headers[group.paths[0]] = tag
+		}
+	}
+
+	var document yamlv3.Node
+	if err := yamlv3.Unmarshal(data, &document); err != nil {
+		return nil, err
+	}
+	if len(document.Content) == 0 {
+		return data, nil
+	}
+	root := document.Content[0]
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		if root.Content[i].Value != "paths" {
+			continue
+		}
+		paths := root.Content[i+1]
+		for j := 0; j < len(paths.Content); j += 2 {
+			if header, ok := headers[paths.Content[j].Value]; ok {
+				paths.Content[j].HeadComment = header
+			}
+		}
+	}
+
+	var out bytes.Buffer
+	encoder := yamlv3.NewEncoder(&out)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(&document); err != nil {
+		return nil, err
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, err
+	}
+	return out.Bytes(), nil
+}
@@ -0,0 +1,147 @@
+package main
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// The subset of the HTTP Archive (HAR) format needed to document traffic
+type harFile struct {
+	Log struct {
+		Entries []harEntry `json:"entries"`
+	} `json:"log"`
+}
+
+type harEntry struct {
+	Request struct {
+		Method string `json:"method"`
+		URL    string `json:"url"`
+	} `json:"request"`
+	Response struct {
+		Status  int `json:"status"`
+		Content struct {
+			MimeType string `json:"mimeType"`
+			Text     string `json:"text"`
+			Encoding string `json:"encoding"`
+		} `json:"content"`
+	} `json:"response"`
+}
+
+// Populate an existing Swagger YAML file from the requests captured in a
+// HAR file. Each entry becomes an operation keyed by its normalized path
+// and method, with a response schema generated from any JSON body.
+func importHAR(filePath, harPath string) error {
+	unlock, err := lockFile(filePath)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	swagger, err := readSwaggerFile(filePath)
+	if err != nil {
+		return err
+	}
+
+	data, err := ioutil.ReadFile(harPath)
+	if err != nil {
+		return err
+	}
+	var har harFile
+	if err := json.Unmarshal(data, &har); err != nil {
+		return err
+	}
+
+	pathCount := len(swagger.Paths)
+	imported := 0
+	for i, entry := range har.Log.Entries {
+		if err := importHAREntry(swagger, entry); err != nil {
+			fmt.Printf("Skipping entry %d (%s %s): %v\n", i, entry.Request.Method, entry.Request.URL, err)
+			continue
+		}
+		imported++
+	}
+	if err := checkPathLimit(swagger, pathCount); err != nil {
+		return err
+	}
+	fmt.Printf("Imported %d of %d HAR entries.\n", imported, len(har.Log.Entries))
+
+	return writeSwaggerFile(outputFile(filePath), swagger)
+}
+
+// Add a single HAR entry to the spec
+func importHAREntry(swagger *SwaggerTemplate, entry harEntry) error {
+	requestURL, err := url.Parse(entry.Request.URL)
+	if err != nil {
+		return err
+	}
+	if entry.Response.Status == 0 {
+		return fmt.Errorf("request has no response")
+	}
+
+	path, params := normalizeHARPath(requestURL.Path)
+	path = prefixImportedPath(path)
+	method := strings.ToLower(entry.Request.Method)
+	status := strconv.Itoa(entry.Response.Status)
+	response := Response{Description: responseDescription(status)}
+
+	content := entry.Response.Content
+	if strings.Contains(content.MimeType, "json") && content.Text != "" {
+		body := []byte(content.Text)
+		if content.Encoding == "base64" {
+			if body, err = base64.StdEncoding.DecodeString(content.Text); err != nil {
+				return err
+			}
+		}
+
+		schema, err := inferSampleSchema(string(body))
+		if err != nil {
+			return err
+		}
+		response.Content = map[string]MediaType{
+			"application/json": {Schema: schema},
+		}
+	}
+
+	setOperationResponse(swagger, path, method, status, response)
+
+	operation := swagger.Paths[path].Operations[method]
+	if len(operation.Parameters) == 0 {
+		operation.Parameters = params
+		swagger.Paths[path].Operations[method] = operation
+	}
+	return nil
+}
+
+// Replace numeric path segments with path parameters, returning the
+// templated path and the parameters it declares. The first ID is named
+// {id}, later ones {id2}, {id3} and so on to keep names unique.
+func normalizeHARPath(rawPath string) (string, []Parameter) {
+	if rawPath == "" {
+		rawPath = "/"
+	}
+
+	var params []Parameter
+	segments := strings.Split(rawPath, "/")
+	for i, segment := range segments {
+		if _, err := strconv.ParseUint(segment, 10, 64); err != nil {
+			continue
+		}
+		name := "id"
+		if len(params) > 0 {
+			name = "id" + strconv.Itoa(len(params)+1)
+		}
+		segments[i] = "{" + name + "}"
+		params = append(params, Parameter{
+			Name:     name,
+			In:       "path",
+			Required: true,
+			Schema:   &Schema{Type: "integer"},
+		})
+	}
+	return strings.Join(segments, "/"), params
+}
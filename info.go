@@ -0,0 +1,149 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	infoTitle       = flag.String("title", "", "API title to set with create or set-info")
+	infoVersion     = flag.String("version", "", "API version to set with create or set-info")
+	infoDescription = flag.String("desc", "", "API description to set with create or set-info")
+	infoTerms       = flag.String("terms", "", "terms of service URL to set with create or set-info")
+	infoSummary     = flag.String("summary", "", "short API summary to set with create or set-info; OpenAPI 3.1 only")
+	versionBump     = flag.String("bump", "", "have set-info increment the semver info.version: major, minor or patch")
+)
+
+// A semantic version, optionally prefixed with v, as in semver.org
+var semverPattern = regexp.MustCompile(`^(v?)(0|[1-9]\d*)\.(0|[1-9]\d*)\.(0|[1-9]\d*)(-[0-9A-Za-z.-]+)?(?:\+[0-9A-Za-z.-]+)?$`)
+
+// Update the title, version, description, terms of service and, for 3.1,
+// summary in the Info block of an existing Swagger YAML file. Values come
+// from the -title, -version, -desc, -terms and -summary flags; when none
+// are given the user is prompted for each one instead. Keys left blank, and
+// any other Info entries such as contact or license, are kept as they are.
+func setInfo(filePath string, reader *bufio.Reader) error {
+	unlock, err := lockFile(filePath)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	swagger, err := readSwaggerFile(filePath)
+	if err != nil {
+		return err
+	}
+
+	updates := infoFlagUpdates()
+	if *versionBump != "" {
+		if *infoVersion != "" {
+			return fmt.Errorf("-bump and -version can't be used together")
+		}
+		current, _ := swagger.Info["version"].(string)
+		if updates["version"], err = bumpVersion(current, *versionBump); err != nil {
+			return err
+		}
+		fmt.Printf("Bumped version from %s to %s.\n", current, updates["version"])
+	} else if *infoTitle == "" && *infoVersion == "" && *infoDescription == "" && *infoTerms == "" && *infoSummary == "" {
+		keys := []string{"title", "version", "description", "termsOfService"}
+		if supportsInfoSummary(swagger.OpenAPI) {
+			keys = append(keys, "summary")
+		}
+		for _, key := range keys {
+			updates[key] = prompt(reader, fmt.Sprintf("Enter new %s (current: %v, blank to keep): ", key, swagger.Info[key]))
+		}
+	}
+
+	if swagger.Info == nil {
+		swagger.Info = make(map[string]interface{})
+	}
+	if err := applyInfoUpdates(swagger, updates); err != nil {
+		return err
+	}
+
+	return writeSwaggerFile(outputFile(filePath), swagger)
+}
+
+// The Info entries set by the -title, -version, -desc, -terms and -summary
+// flags, blank for those not given
+func infoFlagUpdates() map[string]string {
+	return map[string]string{
+		"title":          *infoTitle,
+		"version":        *infoVersion,
+		"description":    *infoDescription,
+		"termsOfService": *infoTerms,
+		"summary":        *infoSummary,
+	}
+}
+
+// Set the non-blank Info entries in updates, after checking that the terms
+// of service look like a URL and that a summary is only set on 3.1 specs
+func applyInfoUpdates(swagger *SwaggerTemplate, updates map[string]string) error {
+	if terms := updates["termsOfService"]; terms != "" {
+		if err := checkTermsOfService(terms); err != nil {
+			return err
+		}
+	}
+	if updates["summary"] != "" && !supportsInfoSummary(swagger.OpenAPI) {
+		return fmt.Errorf("info.summary needs OpenAPI 3.1, but the spec is %s", swagger.OpenAPI)
+	}
+
+	for key, value := range updates {
+		if value != "" {
+			swagger.Info[key] = value
+		}
+	}
+	return nil
+}
+
+// Report whether a spec version has info.summary, which was added in 3.1
+func supportsInfoSummary(openAPIVersion string) bool {
+	return !strings.HasPrefix(openAPIVersion, "3.0")
+}
+
+// Check that a terms of service value is roughly an absolute URL
+func checkTermsOfService(terms string) error {
+	u, err := url.Parse(terms)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return fmt.Errorf("termsOfService %q is not a URL", terms)
+	}
+	return nil
+}
+
+// Increment the major, minor or patch part of a semantic version, resetting
+// the parts below it. Pre-release and build suffixes are dropped. As with
+// npm version, a pre-release of the target version is released as is, so
+// bumping 1.2.3-rc.1 by patch gives 1.2.3 and 2.0.0-beta by major 2.0.0.
+func bumpVersion(version, part string) (string, error) {
+	match := semverPattern.FindStringSubmatch(version)
+	if match == nil {
+		return "", fmt.Errorf("info.version %q is not a valid semantic version", version)
+	}
+	major, _ := strconv.Atoi(match[2])
+	minor, _ := strconv.Atoi(match[3])
+	patch, _ := strconv.Atoi(match[4])
+
+	prerelease := match[5] != ""
+	switch part {
+	case "major":
+		if !prerelease || minor != 0 || patch != 0 {
+			major, minor, patch = major+1, 0, 0
+		}
+	case "minor":
+		if !prerelease || patch != 0 {
+			minor, patch = minor+1, 0
+		}
+	case "patch":
+		if !prerelease {
+			patch++
+		}
+	default:
+		return "", fmt.Errorf("invalid -bump %q: use major, minor or patch", part)
+	}
+	return fmt.Sprintf("%s%d.%d.%d", match[1], major, minor, patch), nil
+}
@@ -0,0 +1,229 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"gopkg.in/yaml.v2"
+)
+
+// Copies external schemas into a spec's components, remembering the
+// documents loaded and the component each external ref became
+type internalizer struct {
+	swagger *SwaggerTemplate
+	docs    map[string]interface{}
+	names   map[string]string
+	report  []string
+}
+
+// Copy every schema an external $ref points to, in another file or at a
+// URL, into components.schemas, and rewrite the refs to point there. Refs
+// inside the copied schemas are followed as well, so the result has no
+// external refs left.
+func internalizeRefs(filePath string) error {
+	unlock, err := lockFile(filePath)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	swagger, err := readSwaggerFile(filePath)
+	if err != nil {
+		return err
+	}
+	if swagger.Components.Schemas == nil {
+		swagger.Components.Schemas = make(map[string]Schema)
+	}
+
+	in := &internalizer{
+		swagger: swagger,
+		docs:    make(map[string]interface{}),
+		names:   make(map[string]string),
+	}
+	walkSpecSchemas(swagger, func(schema *Schema, location string) {
+		if err != nil || schema.Ref == "" || strings.HasPrefix(schema.Ref, "#") {
+			return
+		}
+		var name string
+		if name, err = in.internalize(resolveRefLocation(filePath, schema.Ref)); err != nil {
+			err = fmt.Errorf("%s: %w", location, err)
+			return
+		}
+		schema.Ref = schemaRefPrefix + name
+	})
+	if err != nil {
+		return err
+	}
+
+	if len(in.report) == 0 {
+		fmt.Println("No external refs found.")
+		return nil
+	}
+	for _, line := range in.report {
+		fmt.Println(line)
+	}
+	return writeSwaggerFile(outputFile(filePath), swagger)
+}
+
+// Copy the schema an absolute ref points to into components, returning the
+// name of the component it became. Each ref is copied once, and named
+// before the refs inside it are followed, so cycles end.
+func (in *internalizer) internalize(ref string) (string, error) {
+	if name, ok := in.names[ref]; ok {
+		return name, nil
+	}
+
+	location, pointer, _ := strings.Cut(ref, "#")
+	node, err := in.resolve(location, pointer)
+	if err != nil {
+		return "", err
+	}
+	data, err := yaml.Marshal(node)
+	if err != nil {
+		return "", err
+	}
+	var schema Schema
+	if err := yaml.Unmarshal(data, &schema); err != nil {
+		return "", fmt.Errorf("%s is not a schema: %w", ref, err)
+	}
+
+	name := uniqueComponentName(in.swagger, externalSchemaName(location, pointer), schema, "")
+	in.names[ref] = name
+	if _, exists := in.swagger.Components.Schemas[name]; exists {
+		in.report = append(in.report, fmt.Sprintf("%s is identical to %s, reused it", ref, name))
+		return name, nil
+	}
+	in.swagger.Components.Schemas[name] = schema
+	in.report = append(in.report, fmt.Sprintf("Internalized %s as %s", ref, name))
+
+	// Refs inside the copied schema are relative to the document it came from
+	walkSchema(&schema, name, func(nested *Schema, _ string) {
+		if err != nil || nested.Ref == "" {
+			return
+		}
+		var nestedName string
+		if nestedName, err = in.internalize(resolveRefLocation(location, nested.Ref)); err != nil {
+			return
+		}
+		nested.Ref = schemaRefPrefix + nestedName
+	})
+	if err != nil {
+		return "", err
+	}
+	in.swagger.Components.Schemas[name] = schema
+	return name, nil
+}
+
+// Find the node a JSON pointer names in the document at location, loading
+// the document on first use
+func (in *internalizer) resolve(location, pointer string) (interface{}, error) {
+	doc, ok := in.docs[location]
+	if !ok {
+		data, err := readRefDocument(location)
+		if err != nil {
+			return nil, err
+		}
+		if bytes.HasPrefix(bytes.TrimSpace(data), []byte("{")) {
+			err = decodeJSON(data, &doc)
+			doc = sampleValue(doc)
+		} else {
+			err = yaml.Unmarshal(data, &doc)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("parsing %s: %w", location, err)
+		}
+		in.docs[location] = doc
+	}
+
+	node := doc
+	for _, token := range strings.Split(strings.TrimPrefix(pointer, "/"), "/") {
+		if token == "" {
+			continue
+		}
+		token = strings.NewReplacer("~1", "/", "~0", "~").Replace(token)
+		switch v := node.(type) {
+		case map[interface{}]interface{}:
+			node, ok = v[token]
+		case map[string]interface{}:
+			node, ok = v[token]
+		case []interface{}:
+			i, err := strconv.Atoi(token)
+			ok = err == nil && i >= 0 && i < len(v)
+			if ok {
+				node = v[i]
+			}
+		default:
+			ok = false
+		}
+		if !ok {
+			return nil, fmt.Errorf("%s#%s not found", location, pointer)
+		}
+	}
+	return node, nil
+}
+
+// Read a referenced document from a file or an http(s) URL
+func readRefDocument(location string) ([]byte, error) {
+	if !strings.HasPrefix(location, "http://") && !strings.HasPrefix(location, "https://") {
+		return ioutil.ReadFile(location)
+	}
+	resp, err := http.Get(location)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetching %s: %s", location, resp.Status)
+	}
+	return ioutil.ReadAll(resp.Body)
+}
+
+// Turn a ref found in the document at base into an absolute one: a local
+// #/pointer stays in base, and relative files and URLs are resolved
+// against base's directory
+func resolveRefLocation(base, ref string) string {
+	location, pointer, hasPointer := strings.Cut(ref, "#")
+	switch {
+	case location == "":
+		location = base
+	case strings.Contains(base, "://") || strings.Contains(location, "://"):
+		if baseURL, err := url.Parse(base); err == nil {
+			if refURL, err := url.Parse(location); err == nil {
+				location = baseURL.ResolveReference(refURL).String()
+			}
+		}
+	case !filepath.IsAbs(location):
+		location = filepath.Join(filepath.Dir(base), location)
+	}
+	if hasPointer {
+		return location + "#" + pointer
+	}
+	return location
+}
+
+// Derive a component name for an external schema from the last part of
+// its pointer, or the document's file name when it is the whole document
+func externalSchemaName(location, pointer string) string {
+	base := pointer[strings.LastIndex(pointer, "/")+1:]
+	if base == "" {
+		base = strings.TrimSuffix(path.Base(location), path.Ext(location))
+	}
+	base = strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || strings.ContainsRune(".-_", r) {
+			return r
+		}
+		return '_'
+	}, strings.NewReplacer("~1", "/", "~0", "~").Replace(base))
+	if !isValidComponentName(base) {
+		return "External"
+	}
+	return base
+}
@@ -0,0 +1,162 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+const jsonSchemaDialect = "https://json-schema.org/draft/2020-12/schema"
+
+// Export a component schema as a standalone JSON Schema document. The
+// components it refers to, directly or in turn, go under $defs.
+func exportJSONSchema(filePath, name, outputPath string) error {
+	swagger, err := readSwaggerFile(filePath)
+	if err != nil {
+		return err
+	}
+	schema, ok := swagger.Components.Schemas[name]
+	if !ok {
+		return fmt.Errorf("component schema %s not found", name)
+	}
+
+	refs, err := componentRefs(schema)
+	if err != nil {
+		return err
+	}
+	defs, err := referencedComponents(swagger, refs)
+	if err != nil {
+		return err
+	}
+	delete(defs, name)
+
+	root, err := jsonSchemaValue(schema, name)
+	if err != nil {
+		return err
+	}
+	document := append(yaml.MapSlice{{Key: "$schema", Value: jsonSchemaDialect}}, root...)
+	if len(defs) > 0 {
+		var converted yaml.MapSlice
+		for _, defName := range sortedKeys(defs) {
+			def, err := jsonSchemaValue(defs[defName], name)
+			if err != nil {
+				return err
+			}
+			converted = append(converted, yaml.MapItem{Key: defName, Value: def})
+		}
+		document = append(document, yaml.MapItem{Key: "$defs", Value: converted})
+	}
+
+	var buf bytes.Buffer
+	if err := writeOrderedJSON(&buf, document); err != nil {
+		return err
+	}
+	var indented bytes.Buffer
+	if err := json.Indent(&indented, buf.Bytes(), "", "  "); err != nil {
+		return err
+	}
+	indented.WriteByte('\n')
+
+	if outputPath == "" || outputPath == "-" {
+		fmt.Print(indented.String())
+		return nil
+	}
+	if err := ioutil.WriteFile(outputPath, indented.Bytes(), 0644); err != nil {
+		return err
+	}
+	fmt.Println("JSON Schema written successfully.")
+	return nil
+}
+
+// Convert a schema to JSON Schema in the same key order it is written in.
+// root is the name of the exported component, whose refs point at the
+// document itself.
+func jsonSchemaValue(schema Schema, root string) (yaml.MapSlice, error) {
+	data, err := yaml.Marshal(schema)
+	if err != nil {
+		return nil, err
+	}
+	var node yaml.MapSlice
+	if err := yaml.Unmarshal(data, &node); err != nil {
+		return nil, err
+	}
+	return jsonSchemaNode(node, root), nil
+}
+
+// Rewrite an OpenAPI schema node, decoded generically, as JSON Schema:
+// component refs point into $defs, nullable becomes a "null" type, an
+// example becomes examples and x- extensions are dropped. Only keywords
+// holding schemas are descended into, so property names and enum values
+// are never rewritten.
+func jsonSchemaNode(node yaml.MapSlice, root string) yaml.MapSlice {
+	converted := make(yaml.MapSlice, 0, len(node))
+	nullable := false
+	for _, item := range node {
+		key := fmt.Sprint(item.Key)
+		switch {
+		case key == "$ref":
+			ref := fmt.Sprint(item.Value)
+			if name := strings.TrimPrefix(ref, schemaRefPrefix); name != ref {
+				ref = "#/$defs/" + name
+				if name == root {
+					ref = "#"
+				}
+			}
+			item.Value = ref
+		case key == "nullable":
+			nullable = item.Value == true
+			continue
+		case key == "example":
+			item = yaml.MapItem{Key: "examples", Value: []interface{}{item.Value}}
+		case strings.HasPrefix(key, "x-"):
+			continue
+		case key == "properties":
+			if properties, ok := item.Value.(yaml.MapSlice); ok {
+				convertedProperties := make(yaml.MapSlice, len(properties))
+				for i, property := range properties {
+					if schema, ok := property.Value.(yaml.MapSlice); ok {
+						property.Value = jsonSchemaNode(schema, root)
+					}
+					convertedProperties[i] = property
+				}
+				item.Value = convertedProperties
+			}
+		case key == "items" || key == "additionalProperties":
+			if schema, ok := item.Value.(yaml.MapSlice); ok {
+				item.Value = jsonSchemaNode(schema, root)
+			}
+		case key == "allOf":
+			if members, ok := item.Value.([]interface{}); ok {
+				convertedMembers := make([]interface{}, len(members))
+				for i, member := range members {
+					if schema, ok := member.(yaml.MapSlice); ok {
+						member = jsonSchemaNode(schema, root)
+					}
+					convertedMembers[i] = member
+				}
+				item.Value = convertedMembers
+			}
+		}
+		converted = append(converted, item)
+	}
+
+	if nullable {
+		for i, item := range converted {
+			switch {
+			case item.Key == "type":
+				if schemaType, ok := item.Value.(string); ok {
+					converted[i].Value = []interface{}{schemaType, "null"}
+				}
+			case item.Key == "enum":
+				if values, ok := item.Value.([]interface{}); ok && !enumAllowsNull(values) {
+					converted[i].Value = append(values, nil)
+				}
+			}
+		}
+	}
+	return converted
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Add a link to an operation's response, leading to the operation with the
+// given operationId. params is a comma-separated list of name=expression
+// pairs, e.g. petId=$response.body#/id.
+func addLink(filePath, path, method, status, name, operationID, params string) error {
+	if !isValidComponentName(name) {
+		return fmt.Errorf("invalid link name %q: use letters, digits, '.', '-' and '_' only", name)
+	}
+	link := Link{OperationID: operationID}
+	for _, pair := range strings.Split(params, ",") {
+		if pair = strings.TrimSpace(pair); pair == "" {
+			continue
+		}
+		key, expression, ok := strings.Cut(pair, "=")
+		if !ok || strings.TrimSpace(key) == "" {
+			return fmt.Errorf("invalid parameter %q: expected name=expression", pair)
+		}
+		if link.Parameters == nil {
+			link.Parameters = make(map[string]interface{})
+		}
+		link.Parameters[strings.TrimSpace(key)] = strings.TrimSpace(expression)
+	}
+
+	unlock, err := lockFile(filePath)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	swagger, err := readSwaggerFile(filePath)
+	if err != nil {
+		return err
+	}
+
+	if _, _, ok := findOperationByID(swagger, operationID); !ok {
+		return fmt.Errorf("no operation has operationId %q", operationID)
+	}
+	operation, ok := swagger.Paths[path].Operations[method]
+	if !ok {
+		return fmt.Errorf("operation %s %s not found", strings.ToUpper(method), path)
+	}
+	response, ok := operation.Responses[status]
+	if !ok {
+		return fmt.Errorf("operation %s %s has no %s response", strings.ToUpper(method), path, status)
+	}
+
+	if response.Links == nil {
+		response.Links = make(map[string]Link)
+	}
+	response.Links[name] = link
+	operation.Responses[status] = response
+	swagger.Paths[path].Operations[method] = operation
+	return writeSwaggerFile(outputFile(filePath), swagger)
+}
+
+// Find the path and method of the operation with an operationId
+func findOperationByID(swagger *SwaggerTemplate, operationID string) (string, string, bool) {
+	for _, path := range sortedKeys(swagger.Paths) {
+		for _, method := range sortedKeys(swagger.Paths[path].Operations) {
+			if swagger.Paths[path].Operations[method].OperationID == operationID {
+				return path, method, true
+			}
+		}
+	}
+	return "", "", false
+}
+
+// Check that every response link names its operation exactly once, by an
+// operationId of an existing operation or by an operationRef
+func validateLinks(swagger *SwaggerTemplate) []string {
+	var problems []string
+	for _, path := range sortedKeys(swagger.Paths) {
+		for _, method := range sortedKeys(swagger.Paths[path].Operations) {
+			operation := swagger.Paths[path].Operations[method]
+			for _, status := range sortedKeys(operation.Responses) {
+				links := operation.Responses[status].Links
+				for _, name := range sortedKeys(links) {
+					location := fmt.Sprintf("paths.%s.%s.responses.%s.links.%s", path, method, status, name)
+					link := links[name]
+					problems = append(problems, validateExtensions(link.Extensions, location, nil)...)
+					switch {
+					case link.Ref != "":
+					case link.OperationID == "" && link.OperationRef == "":
+						problems = append(problems, location+": missing operationId or operationRef")
+					case link.OperationID != "" && link.OperationRef != "":
+						problems = append(problems, location+": operationId and operationRef are mutually exclusive")
+					case link.OperationID != "":
+						if _, _, ok := findOperationByID(swagger, link.OperationID); !ok {
+							problems = append(problems, fmt.Sprintf("%s: no operation has operationId %q", location, link.OperationID))
+						}
+					}
+					if link.Server != nil {
+						if err := validateServerURL(link.Server.URL); err != nil {
+							problems = append(problems, fmt.Sprintf("%s.server: %v", location, err))
+						}
+					}
+				}
+			}
+		}
+	}
+	return problems
+}
@@ -0,0 +1,166 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+var lintFix = flag.Bool("fix", false, "have lint correct issues that are safe to fix automatically and write the spec back")
+
+// A problem found by lint. Errors make lint fail, warnings are advisory.
+type lintIssue struct {
+	severity string
+	location string
+	message  string
+}
+
+func (issue lintIssue) String() string {
+	return fmt.Sprintf("%s: %s: %s", issue.severity, issue.location, issue.message)
+}
+
+// Check a spec for common mistakes and omissions, printing each issue.
+// With -fix, safe fixes are applied first and the spec is written back once
+// all of them are done; anything else is left for the author.
+func lintSwaggerFile(filePath string) error {
+	unlock, err := lockFile(filePath)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	swagger, err := readSwaggerFile(filePath)
+	if err != nil {
+		return err
+	}
+
+	issues, fixes := lintSwagger(swagger, *lintFix)
+	for _, fix := range fixes {
+		fmt.Println("fixed:", fix)
+	}
+	errorCount := 0
+	for _, issue := range issues {
+		fmt.Println(issue)
+		if issue.severity == "error" {
+			errorCount++
+		}
+	}
+
+	if len(fixes) > 0 {
+		if err := writeSwaggerFile(outputFile(filePath), swagger); err != nil {
+			return err
+		}
+	}
+	if errorCount > 0 {
+		return fmt.Errorf("%d errors and %d warnings found", errorCount, len(issues)-errorCount)
+	}
+	if len(issues) == 0 {
+		fmt.Println("No lint issues found.")
+	}
+	return nil
+}
+
+// Lint a spec, returning the issues found. When fix is set, clearly safe
+// fixes are made in place instead of being reported, and a description of
+// each change is returned.
+func lintSwagger(swagger *SwaggerTemplate, fix bool) ([]lintIssue, []string) {
+	var issues []lintIssue
+	var fixes []string
+
+	operationIDs := make(map[string]string)
+	for _, path := range sortedKeys(swagger.Paths) {
+		for _, method := range sortedKeys(swagger.Paths[path].Operations) {
+			if operation := swagger.Paths[path].Operations[method]; operation.OperationID != "" {
+				location := fmt.Sprintf("paths.%s.%s", path, method)
+				if previous, taken := operationIDs[operation.OperationID]; taken {
+					issues = append(issues, lintIssue{"error", location, fmt.Sprintf("operationId %q is already used by %s", operation.OperationID, previous)})
+				}
+				operationIDs[operation.OperationID] = location
+			}
+		}
+	}
+
+	for _, path := range sortedKeys(swagger.Paths) {
+		for _, method := range sortedKeys(swagger.Paths[path].Operations) {
+			operation := swagger.Paths[path].Operations[method]
+			location := fmt.Sprintf("paths.%s.%s", path, method)
+
+			if operation.Summary == "" {
+				issues = append(issues, lintIssue{"warning", location, "missing summary"})
+			}
+			if operation.OperationID == "" {
+				if fix {
+					operation.OperationID = uniqueOperationID(operationIDs, generateOperationID(path, method))
+					operationIDs[operation.OperationID] = location
+					fixes = append(fixes, fmt.Sprintf("%s: set operationId to %q", location, operation.OperationID))
+				} else {
+					issues = append(issues, lintIssue{"warning", location, "missing operationId"})
+				}
+			}
+
+			for _, status := range sortedKeys(operation.Responses) {
+				response := operation.Responses[status]
+				if response.Description != "" {
+					continue
+				}
+				responseLocation := fmt.Sprintf("%s.responses.%s", location, status)
+				if fix {
+					response.Description = responseDescription(status)
+					operation.Responses[status] = response
+					fixes = append(fixes, fmt.Sprintf("%s: set description to %q", responseLocation, response.Description))
+				} else {
+					issues = append(issues, lintIssue{"error", responseLocation, "missing description"})
+				}
+			}
+
+			swagger.Paths[path].Operations[method] = operation
+		}
+	}
+
+	walkSpecSchemas(swagger, func(schema *Schema, location string) {
+		if schema.Type != "array" || schema.Items != nil {
+			return
+		}
+		if fix {
+			schema.Items = &Schema{Type: "string"}
+			fixes = append(fixes, location+": added string items to array schema")
+		} else {
+			issues = append(issues, lintIssue{"error", location, "array schema is missing items"})
+		}
+	})
+
+	// Required and nullable together is valid, but usually only one was meant
+	walkSpecSchemas(swagger, func(schema *Schema, location string) {
+		for _, name := range schema.Required {
+			if prop, ok := schema.Properties[name]; ok && (prop.Nullable || prop.nullType) {
+				issues = append(issues, lintIssue{"warning", joinKeyPath(location, name), "property is required but nullable; confirm that null is an intended value"})
+			}
+		}
+	})
+
+	issues = append(issues, exampleMismatches(swagger)...)
+	return issues, fixes
+}
+
+// Generate an operationId from the method and path, e.g. GET /pets/{id}
+// becomes getPetsId
+func generateOperationID(path, method string) string {
+	var sb strings.Builder
+	sb.WriteString(strings.ToLower(method))
+	for _, word := range strings.FieldsFunc(path, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		sb.WriteString(capitalize(word))
+	}
+	return sb.String()
+}
+
+// Add a numeric suffix to an operationId until it is unused
+func uniqueOperationID(used map[string]string, base string) string {
+	id := base
+	for i := 2; used[id] != ""; i++ {
+		id = fmt.Sprintf("%s%d", base, i)
+	}
+	return id
+}
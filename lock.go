@@ -0,0 +1,78 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+)
+
+const (
+	lockTimeout       = 5 * time.Second
+	lockRetryInterval = 100 * time.Millisecond
+)
+
+var (
+	heldLocksMu   sync.Mutex
+	heldLocks     = make(map[string]bool)
+	interruptOnce sync.Once
+)
+
+// Take an advisory lock on a spec file so that concurrent processes don't
+// interleave their read-modify-write cycles. The lock is a sibling
+// "<file>.lock" file created exclusively; when another process holds it we
+// retry for a short while before giving up. The returned function releases
+// the lock and must be called on every path, typically via defer. Locks
+// are also removed if the process is interrupted, e.g. by Ctrl+C at a
+// prompt, so that an abandoned edit doesn't block later runs.
+func lockFile(filePath string) (func(), error) {
+	interruptOnce.Do(removeLocksOnInterrupt)
+	lockPath := filePath + ".lock"
+	deadline := time.Now().Add(lockTimeout)
+
+	for {
+		lock, err := os.OpenFile(lockPath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+		if err == nil {
+			fmt.Fprintf(lock, "%d\n", os.Getpid())
+			lock.Close()
+			heldLocksMu.Lock()
+			heldLocks[lockPath] = true
+			heldLocksMu.Unlock()
+			return func() {
+				heldLocksMu.Lock()
+				defer heldLocksMu.Unlock()
+				delete(heldLocks, lockPath)
+				os.Remove(lockPath)
+			}, nil
+		}
+		if !errors.Is(err, os.ErrExist) {
+			return nil, err
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("%s is being edited by another process; if no other process is running, remove %s and try again", filePath, lockPath)
+		}
+		time.Sleep(lockRetryInterval)
+	}
+}
+
+// Remove the locks still held when an interrupt or termination signal
+// arrives, then exit as the signal would have
+func removeLocksOnInterrupt() {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		sig := <-signals
+		heldLocksMu.Lock()
+		for lockPath := range heldLocks {
+			os.Remove(lockPath)
+		}
+		exitCode := 1
+		if number, ok := sig.(syscall.Signal); ok {
+			exitCode = 128 + int(number)
+		}
+		os.Exit(exitCode)
+	}()
+}
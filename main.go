@@ -2,50 +2,253 @@ package main
 
 import (
 	"bufio"
+	"bytes"
 	"encoding/json"
+	"flag"
 	"fmt"
 	"gopkg.in/yaml.v2"
+	"io"
 	"io/ioutil"
+	"mime"
+	"net/http"
+	"net/url"
 	"os"
+	"path/filepath"
 	"reflect"
+	"strconv"
 	"strings"
+	"time"
+	"unicode"
 )
 
-// Define the basic Swagger structure
+// Define the basic Swagger structure. Top-level keys without a field of
+// their own, such as x- vendor extensions, are kept in Extensions so they
+// survive a round trip.
 type SwaggerTemplate struct {
-	OpenAPI string                          `yaml:"openapi"`
-	Info    map[string]interface{}          `yaml:"info"`
-	Paths   map[string]map[string]Operation `yaml:"paths"`
+	OpenAPI    string                 `yaml:"openapi"`
+	Info       map[string]interface{} `yaml:"info"`
+	Servers    []Server               `yaml:"servers,omitempty"`
+	Paths      map[string]PathItem    `yaml:"paths"`
+	Components Components             `yaml:"components,omitempty"`
+	Extensions map[string]interface{} `yaml:",inline"`
 }
 
+// Only schemas are modelled. The other sections, such as securitySchemes
+// and responses, are kept as they are in Extensions so they survive a
+// round trip.
+type Components struct {
+	Schemas    map[string]Schema      `yaml:"schemas,omitempty"`
+	Extensions map[string]interface{} `yaml:",inline"`
+}
+
+type Server struct {
+	URL         string `yaml:"url"`
+	Description string `yaml:"description,omitempty"`
+}
+
+// The operations on a path, keyed by lowercase HTTP method. Parameters are
+// shared by every operation on the path; see effectiveParameters.
+type PathItem struct {
+	Parameters []Parameter          `yaml:"parameters,omitempty"`
+	Operations map[string]Operation `yaml:",inline"`
+}
+
+// Responses is required by OpenAPI, so it is written as {} even when empty.
+// Keys without a field, such as gateway x- extensions, go in Extensions.
 type Operation struct {
-	Summary     string               `yaml:"summary"`
-	Responses   map[string]Response  `yaml:"responses"`
-	Description string               `yaml:"description"`
+	Tags        []string               `yaml:"tags,omitempty"`
+	Summary     string                 `yaml:"summary,omitempty"`
+	OperationID string                 `yaml:"operationId,omitempty"`
+	Deprecated  bool                   `yaml:"deprecated,omitempty"`
+	Parameters  []Parameter            `yaml:"parameters,omitempty"`
+	RequestBody *RequestBody           `yaml:"requestBody,omitempty"`
+	Responses   map[string]Response    `yaml:"responses"`
+	Description string                 `yaml:"description,omitempty"`
+	Servers     []Server               `yaml:"servers,omitempty"`
+	Callbacks   map[string]interface{} `yaml:"callbacks,omitempty"`
+	Extensions  map[string]interface{} `yaml:",inline"`
 }
 
-type Response struct {
-	Description string               `yaml:"description"`
+// Keys without a field, such as style and example, go in Extensions
+type Parameter struct {
+	Name        string                 `yaml:"name"`
+	In          string                 `yaml:"in"`
+	Description string                 `yaml:"description,omitempty"`
+	Required    bool                   `yaml:"required,omitempty"`
+	Schema      *Schema                `yaml:"schema,omitempty"`
+	Extensions  map[string]interface{} `yaml:",inline"`
+}
+
+type RequestBody struct {
+	Description string               `yaml:"description,omitempty"`
 	Content     map[string]MediaType `yaml:"content"`
+	Required    bool                 `yaml:"required,omitempty"`
+}
+
+// Description is required by OpenAPI, so it is written even when empty
+type Response struct {
+	Description string                 `yaml:"description"`
+	Content     map[string]MediaType   `yaml:"content,omitempty"`
+	Links       map[string]Link        `yaml:"links,omitempty"`
+	Extensions  map[string]interface{} `yaml:",inline"`
 }
 
+// A design-time link from a response to another operation, with the
+// parameters to call it with as runtime expressions. The operation is
+// named by operationId or by operationRef, unless the link is a $ref to
+// one under components. Keys without a field go in Extensions.
+type Link struct {
+	Ref          string                 `yaml:"$ref,omitempty"`
+	OperationRef string                 `yaml:"operationRef,omitempty"`
+	OperationID  string                 `yaml:"operationId,omitempty"`
+	Parameters   map[string]interface{} `yaml:"parameters,omitempty"`
+	RequestBody  interface{}            `yaml:"requestBody,omitempty"`
+	Description  string                 `yaml:"description,omitempty"`
+	Server       *Server                `yaml:"server,omitempty"`
+	Extensions   map[string]interface{} `yaml:",inline"`
+}
+
+// Keys without a field, such as examples, go in Extensions
 type MediaType struct {
-	Schema Schema `yaml:"schema"`
+	Schema     Schema                 `yaml:"schema,omitempty"`
+	Example    interface{}            `yaml:"example,omitempty"`
+	Encoding   map[string]Encoding    `yaml:"encoding,omitempty"`
+	Extensions map[string]interface{} `yaml:",inline"`
+}
+
+// How one property of a form body is encoded, keyed by property name in
+// MediaType.Encoding. Only used for multipart and urlencoded bodies.
+type Encoding struct {
+	ContentType   string                 `yaml:"contentType,omitempty"`
+	Style         string                 `yaml:"style,omitempty"`
+	Explode       *bool                  `yaml:"explode,omitempty"`
+	AllowReserved bool                   `yaml:"allowReserved,omitempty"`
+	Headers       map[string]interface{} `yaml:"headers,omitempty"`
+	Extensions    map[string]interface{} `yaml:",inline"`
 }
 
 type Schema struct {
-	Type       string            `yaml:"type"`
-	Properties map[string]Schema `yaml:"properties,omitempty"`
+	Ref           string            `yaml:"$ref,omitempty"`
+	Title         string            `yaml:"title,omitempty"`
+	Description   string            `yaml:"description,omitempty"`
+	Type          string            `yaml:"type,omitempty"`
+	Format        string            `yaml:"format,omitempty"`
+	Nullable      bool              `yaml:"nullable,omitempty"`
+	Required      []string          `yaml:"required,omitempty"`
+	Properties    map[string]Schema `yaml:"properties,omitempty"`
+	Items         *Schema           `yaml:"items,omitempty"`
+	UniqueItems   bool              `yaml:"uniqueItems,omitempty"`
+	MinItems      *int              `yaml:"minItems,omitempty"`
+	MaxItems      *int              `yaml:"maxItems,omitempty"`
+	AllOf         []Schema          `yaml:"allOf,omitempty"`
+	Enum          []interface{}     `yaml:"enum,omitempty"`
+	Example       interface{}       `yaml:"example,omitempty"`
+	Default       interface{}       `yaml:"default,omitempty"`
+	Const         interface{}       `yaml:"const,omitempty"`
+	MinProperties *int              `yaml:"minProperties,omitempty"`
+	MaxProperties *int              `yaml:"maxProperties,omitempty"`
+	Deprecated    bool              `yaml:"deprecated,omitempty"`
+	Sunset        string            `yaml:"x-sunset,omitempty"`
+
+	AdditionalProperties *AdditionalProperties `yaml:"additionalProperties,omitempty"`
+
+	// Keywords without a field, such as oneOf, pattern and minimum, and x-
+	// vendor extensions, kept as they are so they survive a round trip
+	Extensions map[string]interface{} `yaml:",inline"`
+
+	// Order to write Properties in when -sort-properties is off
+	propertyOrder []string
+
+	// Whether the type also allows null, written as a [type, "null"] list
+	// for OpenAPI 3.1, which has no nullable
+	nullType bool
+
+	// Whether the schema was inferred from null sample values only, so its
+	// string type is a placeholder for mergeSchemas to replace
+	nullSample bool
+}
+
+// The additionalProperties of an object schema: either a bool allowing or
+// forbidding properties that aren't listed, or, when Schema is set, the
+// schema their values must match
+type AdditionalProperties struct {
+	Allowed bool
+	Schema  *Schema
+}
+
+// Report whether additionalProperties is false, forbidding unlisted
+// properties. A nil value allows them, as OpenAPI does by default.
+func (a *AdditionalProperties) Forbidden() bool {
+	return a != nil && a.Schema == nil && !a.Allowed
+}
+
+// Decode additionalProperties as a bool or as a schema
+func (a *AdditionalProperties) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var allowed bool
+	if err := unmarshal(&allowed); err == nil {
+		*a = AdditionalProperties{Allowed: allowed}
+		return nil
+	}
+	var schema Schema
+	if err := unmarshal(&schema); err != nil {
+		return err
+	}
+	*a = AdditionalProperties{Schema: &schema}
+	return nil
+}
+
+// Write additionalProperties as the schema when there is one, or the bool
+func (a AdditionalProperties) MarshalYAML() (interface{}, error) {
+	if a.Schema != nil {
+		return a.Schema, nil
+	}
+	return a.Allowed, nil
+}
+
+var (
+	strictTypes  = flag.Bool("strict-types", false, "fail schema generation on values whose type can't be inferred instead of defaulting to string")
+	intFormat    = flag.Bool("int-format", false, "annotate inferred integers with format int32 or int64 based on their magnitude")
+	schemaTitles = flag.Bool("titles", false, "give generated object schemas a title derived from their property name, e.g. firstName becomes \"First Name\"")
+	inferUnique  = flag.Bool("infer-unique", false, "mark sample arrays of more than one distinct scalar as uniqueItems")
+	arrayExample = flag.Bool("array-example", false, "set the example of inferred array item schemas to the first element of the sample array")
+	arraySlice   = flag.Bool("array-example-slice", false, "with -array-example, also set the array's own example to its first two elements")
+	coerceBools  = flag.Bool("coerce-bools", false, "infer the exact string values \"true\" and \"false\" in samples as booleans")
+	dropNulls    = flag.Bool("drop-nulls", false, "leave object keys whose sample value is null out of inferred schemas instead of documenting them as strings; takes precedence over -strict-types, which then doesn't fail on those keys")
+	maxDepth     = flag.Int("max-depth", 0, "stop schema generation from samples this many levels deep, describing deeper objects and arrays without their contents (0 for unlimited)")
+	outputPath   string
+
+	specFile        = flag.String("file", "", "spec file to work on instead of prompting for it; without this flag, pressing enter at the prompt uses $SWAGGER_FILE")
+	templatePath    = flag.String("template", "", "spec file create starts from instead of the built-in minimal structure")
+	forceCreate     = flag.Bool("force", false, "let create overwrite a spec file that already exists")
+	responseStatus  = flag.String("status", "200", "status code update documents the response under")
+	responseExample = flag.Bool("response-example", false, "have update also store the whole JSON sample as the response example")
+	keepSample      = flag.Bool("keep-sample", false, "have update store the sample it inferred the schema from in an x-sample extension on the operation, to show where the schema came from")
+	yamlSample      = flag.String("yaml-sample", "", "YAML sample file for update to infer the schema from instead of prompting for JSON")
+	limitPaths      = flag.Int("limit-paths", 500, "abort an import that would add more than this many paths, as a guard against malformed input (0 for no limit)")
+	contentType     = flag.String("content-type", "application/json", "media type update documents the response under; use text/event-stream for server-sent events, with the sample describing one event's data")
+)
+
+func init() {
+	flag.StringVar(&outputPath, "o", "", "write the result of create, update, convert, merge and other editing actions to this file instead of the input file")
+	flag.StringVar(&outputPath, "output", "", "same as -o")
 }
 
 func main() {
+	flag.Parse()
 	reader := bufio.NewReader(os.Stdin)
 
+	// Run a single action non-interactively when one is given on the command line
+	if flag.NArg() > 0 {
+		if err := runAction(strings.ToLower(flag.Arg(0)), reader); err != nil {
+			fmt.Fprintln(os.Stderr, "Error:", err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	for {
 		// Ask user for the desired action
-		fmt.Print("\nEnter action (view/create/update/exit): ")
-		action, _ := reader.ReadString('\n')
-		action = strings.ToLower(strings.TrimSpace(action))
+		action := strings.ToLower(prompt(reader, "\nEnter action ("+strings.Join(actionNames, "/")+"/exit): "))
 
 		// Exit the program if the user types 'exit'
 		if action == "exit" {
@@ -53,41 +256,547 @@ func main() {
 			break
 		}
 
-		// Handle the desired action
-		switch action {
-		case "view":
-			fmt.Print("Enter the path to the Swagger YAML file: ")
-			filePath, _ := reader.ReadString('\n')
-			filePath = strings.TrimSpace(filePath)
-			err := viewSwagger(filePath)
-			if err != nil {
-				fmt.Println("Error viewing Swagger file:", err)
-			}
-		case "create":
-			fmt.Print("Enter the path to create a new Swagger YAML file: ")
-			filePath, _ := reader.ReadString('\n')
-			filePath = strings.TrimSpace(filePath)
-			err := createSwagger(filePath)
-			if err != nil {
-				fmt.Println("Error creating Swagger file:", err)
-			}
-		case "update":
-			fmt.Print("Enter the path to the Swagger YAML file: ")
-			filePath, _ := reader.ReadString('\n')
-			filePath = strings.TrimSpace(filePath)
-			err := updateSwagger(filePath, reader)
-			if err != nil {
-				fmt.Println("Error updating Swagger file:", err)
+		if err := runAction(action, reader); err != nil {
+			fmt.Println("Error:", err)
+		}
+	}
+}
+
+// Handle the desired action, prompting for whatever input it needs
+func runAction(action string, reader *bufio.Reader) error {
+	switch action {
+	case "view":
+		filePath, err := promptSpecFile(reader, "Enter the path to the Swagger YAML file", true)
+		if err != nil {
+			return err
+		}
+		if err := viewSwagger(filePath); err != nil {
+			return fmt.Errorf("viewing Swagger file: %w", err)
+		}
+	case "create":
+		filePath, err := promptSpecFile(reader, "Enter the path to create a new Swagger YAML file", false)
+		if err != nil {
+			return err
+		}
+		if err := createSwagger(filePath); err != nil {
+			return fmt.Errorf("creating Swagger file: %w", err)
+		}
+	case "update":
+		filePath, err := promptSpecFile(reader, "Enter the path to the Swagger YAML file", true)
+		if err != nil {
+			return err
+		}
+		if err := updateSwagger(filePath, reader); err != nil {
+			return fmt.Errorf("updating Swagger file: %w", err)
+		}
+	case "extract":
+		filePath, err := promptSpecFile(reader, "Enter the path to the Swagger YAML file", true)
+		if err != nil {
+			return err
+		}
+		var tags []string
+		for _, tag := range strings.Split(prompt(reader, "Enter the tags to extract, separated by commas: "), ",") {
+			if tag = strings.TrimSpace(tag); tag != "" {
+				tags = append(tags, tag)
 			}
-		default:
-			fmt.Println("Invalid action. Please enter 'view', 'create', 'update', or 'exit'.")
 		}
+		if len(tags) == 0 {
+			return fmt.Errorf("extracting Swagger file: at least one tag is required")
+		}
+		target := outputPath
+		if target == "" {
+			target = prompt(reader, "Enter the path to write the extracted spec to: ")
+		}
+		if err := extractByTags(filePath, tags, target); err != nil {
+			return fmt.Errorf("extracting Swagger file: %w", err)
+		}
+	case "find":
+		filePath, err := promptSpecFile(reader, "Enter the path to the Swagger YAML file", true)
+		if err != nil {
+			return err
+		}
+		pattern := prompt(reader, "Enter a path glob (e.g., /pets/*): ")
+		if err := findPaths(filePath, pattern); err != nil {
+			return fmt.Errorf("finding paths: %w", err)
+		}
+	case "import-graphql":
+		filePath, err := promptSpecFile(reader, "Enter the path to the Swagger YAML file", true)
+		if err != nil {
+			return err
+		}
+		introspectionPath := prompt(reader, "Enter the path to the GraphQL introspection JSON: ")
+		if err := importGraphQL(filePath, introspectionPath); err != nil {
+			return fmt.Errorf("importing GraphQL schema: %w", err)
+		}
+	case "import-har":
+		filePath, err := promptSpecFile(reader, "Enter the path to the Swagger YAML file", true)
+		if err != nil {
+			return err
+		}
+		harPath := prompt(reader, "Enter the path to the HAR file: ")
+		if err := importHAR(filePath, harPath); err != nil {
+			return fmt.Errorf("importing HAR file: %w", err)
+		}
+	case "add-callback":
+		filePath, err := promptSpecFile(reader, "Enter the path to the Swagger YAML file", true)
+		if err != nil {
+			return err
+		}
+		path := prompt(reader, "Enter the path of the operation (e.g., /subscriptions): ")
+		method := strings.ToLower(prompt(reader, "Enter HTTP method (get/post/put/delete): "))
+		name := prompt(reader, "Enter the callback name (e.g., onEvent): ")
+		expression := prompt(reader, "Enter the callback URL expression (blank for {$request.body#/callbackUrl}): ")
+		if expression == "" {
+			expression = "{$request.body#/callbackUrl}"
+		}
+		if err := addCallback(filePath, path, method, name, expression); err != nil {
+			return fmt.Errorf("adding callback: %w", err)
+		}
+	case "add-encoding":
+		filePath, err := promptSpecFile(reader, "Enter the path to the Swagger YAML file", true)
+		if err != nil {
+			return err
+		}
+		path := prompt(reader, "Enter the path of the operation (e.g., /uploads): ")
+		method := strings.ToLower(prompt(reader, "Enter HTTP method (get/post/put/delete): "))
+		if err := addEncoding(filePath, path, method, reader); err != nil {
+			return fmt.Errorf("adding encoding: %w", err)
+		}
+	case "add-link":
+		filePath, err := promptSpecFile(reader, "Enter the path to the Swagger YAML file", true)
+		if err != nil {
+			return err
+		}
+		path := prompt(reader, "Enter the path of the operation (e.g., /pets): ")
+		method := strings.ToLower(prompt(reader, "Enter HTTP method (get/post/put/delete): "))
+		status := prompt(reader, "Enter the response status code: ")
+		name := prompt(reader, "Enter the link name: ")
+		operationID := prompt(reader, "Enter the operationId the link leads to: ")
+		params := prompt(reader, "Enter comma-separated parameters as name=expression (e.g., petId=$response.body#/id): ")
+		if err := addLink(filePath, path, method, status, name, operationID, params); err != nil {
+			return fmt.Errorf("adding link: %w", err)
+		}
+	case "add-operation-server":
+		filePath, err := promptSpecFile(reader, "Enter the path to the Swagger YAML file", true)
+		if err != nil {
+			return err
+		}
+		if err := addOperationServer(filePath, reader); err != nil {
+			return fmt.Errorf("adding operation server: %w", err)
+		}
+	case "add-path-parameter":
+		filePath, err := promptSpecFile(reader, "Enter the path to the Swagger YAML file", true)
+		if err != nil {
+			return err
+		}
+		path := prompt(reader, "Enter the path to share the parameter on (e.g., /pets/{id}): ")
+		if err := addPathParameter(filePath, path, reader); err != nil {
+			return fmt.Errorf("adding path parameter: %w", err)
+		}
+	case "apply-standard-responses":
+		filePath, err := promptSpecFile(reader, "Enter the path to the Swagger YAML file", true)
+		if err != nil {
+			return err
+		}
+		if err := applyStandardResponses(filePath); err != nil {
+			return fmt.Errorf("applying standard responses: %w", err)
+		}
+	case "annotate-schema":
+		filePath, err := promptSpecFile(reader, "Enter the path to the Swagger YAML file", true)
+		if err != nil {
+			return err
+		}
+		name := prompt(reader, "Enter the component schema name: ")
+		property := prompt(reader, "Enter the property path (e.g., owner.name, blank for the schema itself): ")
+		kind := strings.ToLower(prompt(reader, "Enter the annotation (deprecated/const/properties): "))
+		annotation, err := promptAnnotation(reader, kind)
+		if err != nil {
+			return fmt.Errorf("annotating schema: %w", err)
+		}
+		if err := annotateSchema(filePath, name, property, annotation); err != nil {
+			return fmt.Errorf("annotating schema: %w", err)
+		}
+	case "canonicalize":
+		filePath, err := promptSpecFile(reader, "Enter the path to the Swagger YAML file", true)
+		if err != nil {
+			return err
+		}
+		if err := canonicalizeSwagger(filePath); err != nil {
+			return fmt.Errorf("canonicalizing Swagger file: %w", err)
+		}
+	case "changelog":
+		oldPath := prompt(reader, "Enter the path to the previous Swagger YAML file: ")
+		newPath := prompt(reader, "Enter the path to the new Swagger YAML file: ")
+		target := outputPath
+		if target == "" {
+			target = prompt(reader, "Enter the path to write the changelog to (blank for stdout): ")
+		}
+		if err := changelogSwagger(oldPath, newPath, target); err != nil {
+			return fmt.Errorf("generating changelog: %w", err)
+		}
+	case "check-examples":
+		filePath, err := promptSpecFile(reader, "Enter the path to the Swagger YAML file", true)
+		if err != nil {
+			return err
+		}
+		if err := checkExamples(filePath); err != nil {
+			return fmt.Errorf("checking examples: %w", err)
+		}
+	case "check-refs":
+		filePath, err := promptSpecFile(reader, "Enter the path to the Swagger YAML file", true)
+		if err != nil {
+			return err
+		}
+		if err := checkRefs(filePath); err != nil {
+			return fmt.Errorf("checking refs: %w", err)
+		}
+	case "completion":
+		shell := flag.Arg(1)
+		if shell == "" {
+			shell = prompt(reader, "Enter the shell (bash/zsh): ")
+		}
+		if err := printCompletion(strings.ToLower(shell)); err != nil {
+			return fmt.Errorf("generating completion: %w", err)
+		}
+	case "convert":
+		filePath, err := promptSpecFile(reader, "Enter the path to the Swagger file to convert", true)
+		if err != nil {
+			return err
+		}
+		target := outputPath
+		if target == "" {
+			target = prompt(reader, "Enter the output path (.json for JSON, otherwise YAML): ")
+		}
+		if err := convertSwagger(filePath, target); err != nil {
+			return fmt.Errorf("converting Swagger file: %w", err)
+		}
+	case "curl":
+		filePath, err := promptSpecFile(reader, "Enter the path to the Swagger YAML file", true)
+		if err != nil {
+			return err
+		}
+		path := prompt(reader, "Enter the path of the operation (blank for all operations): ")
+		method := ""
+		if path != "" {
+			method = strings.ToLower(prompt(reader, "Enter HTTP method (get/post/put/delete): "))
+		}
+		if err := printCurlCommands(filePath, path, method); err != nil {
+			return fmt.Errorf("generating curl commands: %w", err)
+		}
+	case "describe":
+		filePath, err := promptSpecFile(reader, "Enter the path to the Swagger YAML file", true)
+		if err != nil {
+			return err
+		}
+		path := prompt(reader, "Enter the path of the operation (e.g., /pets): ")
+		method := strings.ToLower(prompt(reader, "Enter HTTP method (get/post/put/delete): "))
+		if err := describeOperation(filePath, path, method); err != nil {
+			return fmt.Errorf("describing operation: %w", err)
+		}
+	case "diff":
+		oldPath := prompt(reader, "Enter the path to the original Swagger YAML file: ")
+		newPath := prompt(reader, "Enter the path to the changed Swagger YAML file: ")
+		if err := diffSwagger(oldPath, newPath); err != nil {
+			return fmt.Errorf("diffing Swagger files: %w", err)
+		}
+	case "doctor":
+		filePath, err := promptSpecFile(reader, "Enter the path to the Swagger YAML file", false)
+		if err != nil {
+			return err
+		}
+		if err := doctor(filePath); err != nil {
+			return fmt.Errorf("doctor: %w", err)
+		}
+	case "edit":
+		filePath, err := promptSpecFile(reader, "Enter the path to the Swagger YAML file", true)
+		if err != nil {
+			return err
+		}
+		path := prompt(reader, "Enter the path of the operation (e.g., /pets): ")
+		method := strings.ToLower(prompt(reader, "Enter HTTP method (get/post/put/delete): "))
+		if err := editOperation(filePath, path, method, reader); err != nil {
+			return fmt.Errorf("editing operation: %w", err)
+		}
+	case "enums":
+		filePath, err := promptSpecFile(reader, "Enter the path to the Swagger YAML file", true)
+		if err != nil {
+			return err
+		}
+		if err := listEnums(filePath); err != nil {
+			return fmt.Errorf("listing enums: %w", err)
+		}
+	case "export-jsonschema":
+		filePath, err := promptSpecFile(reader, "Enter the path to the Swagger YAML file", true)
+		if err != nil {
+			return err
+		}
+		name := prompt(reader, "Enter the component schema name: ")
+		target := outputPath
+		if target == "" {
+			target = prompt(reader, "Enter the path to write the JSON Schema to (blank for stdout): ")
+		}
+		if err := exportJSONSchema(filePath, name, target); err != nil {
+			return fmt.Errorf("exporting JSON Schema: %w", err)
+		}
+	case "export-ts":
+		filePath, err := promptSpecFile(reader, "Enter the path to the Swagger YAML file", true)
+		if err != nil {
+			return err
+		}
+		target := outputPath
+		if target == "" {
+			target = prompt(reader, "Enter the path to write the TypeScript file to (blank for stdout): ")
+		}
+		if err := exportTypeScript(filePath, target); err != nil {
+			return fmt.Errorf("exporting TypeScript: %w", err)
+		}
+	case "export-postman":
+		filePath, err := promptSpecFile(reader, "Enter the path to the Swagger YAML file", true)
+		if err != nil {
+			return err
+		}
+		collectionPath := prompt(reader, "Enter the path to write the Postman collection to: ")
+		if err := exportPostman(filePath, collectionPath); err != nil {
+			return fmt.Errorf("exporting Postman collection: %w", err)
+		}
+	case "import-postman":
+		collectionPath := prompt(reader, "Enter the path to the Postman collection: ")
+		filePath, err := promptSpecFile(reader, "Enter the path to create the Swagger YAML file", false)
+		if err != nil {
+			return err
+		}
+		if err := importPostman(collectionPath, filePath); err != nil {
+			return fmt.Errorf("importing Postman collection: %w", err)
+		}
+	case "import-proto":
+		filePath, err := promptSpecFile(reader, "Enter the path to the Swagger YAML file", true)
+		if err != nil {
+			return err
+		}
+		descriptorPath := prompt(reader, "Enter the path to the FileDescriptorSet (protoc --descriptor_set_out): ")
+		if err := importProto(filePath, descriptorPath); err != nil {
+			return fmt.Errorf("importing protobuf descriptors: %w", err)
+		}
+	case "import-sql":
+		filePath, err := promptSpecFile(reader, "Enter the path to the Swagger YAML file", true)
+		if err != nil {
+			return err
+		}
+		sqlPath := prompt(reader, "Enter the path to the SQL schema dump: ")
+		if err := importSQL(filePath, sqlPath); err != nil {
+			return fmt.Errorf("importing SQL schema: %w", err)
+		}
+	case "internalize":
+		filePath, err := promptSpecFile(reader, "Enter the path to the Swagger YAML file", true)
+		if err != nil {
+			return err
+		}
+		if err := internalizeRefs(filePath); err != nil {
+			return fmt.Errorf("internalizing refs: %w", err)
+		}
+	case "lint":
+		filePath, err := promptSpecFile(reader, "Enter the path to the Swagger YAML file", true)
+		if err != nil {
+			return err
+		}
+		if err := lintSwaggerFile(filePath); err != nil {
+			return fmt.Errorf("linting Swagger file: %w", err)
+		}
+	case "markdown":
+		filePath, err := promptSpecFile(reader, "Enter the path to the Swagger YAML file", true)
+		if err != nil {
+			return err
+		}
+		target := outputPath
+		if target == "" {
+			target = prompt(reader, "Enter the path to write the Markdown file to (blank for stdout): ")
+		}
+		if err := markdownSwagger(filePath, target); err != nil {
+			return fmt.Errorf("generating Markdown: %w", err)
+		}
+	case "merge":
+		filePath, err := promptSpecFile(reader, "Enter the path to the base Swagger YAML file", true)
+		if err != nil {
+			return err
+		}
+		otherPath := prompt(reader, "Enter the path to the Swagger YAML file to merge in: ")
+		if err := mergeSwagger(filePath, otherPath); err != nil {
+			return fmt.Errorf("merging Swagger files: %w", err)
+		}
+	case "mock":
+		filePath, err := promptSpecFile(reader, "Enter the path to the Swagger YAML file", true)
+		if err != nil {
+			return err
+		}
+		path := prompt(reader, "Enter the path of the operation (e.g., /pets): ")
+		method := strings.ToLower(prompt(reader, "Enter HTTP method (get/post/put/delete): "))
+		status := prompt(reader, "Enter the response status code (blank for "+*responseStatus+"): ")
+		if status == "" {
+			status = *responseStatus
+		}
+		if err := mockResponse(filePath, path, method, status); err != nil {
+			return fmt.Errorf("mocking response: %w", err)
+		}
+	case "normalize-responses":
+		filePath, err := promptSpecFile(reader, "Enter the path to the Swagger YAML file", true)
+		if err != nil {
+			return err
+		}
+		if err := normalizeResponses(filePath); err != nil {
+			return fmt.Errorf("normalizing responses: %w", err)
+		}
+	case "operation-ids":
+		oldPath := prompt(reader, "Enter the path to the original Swagger YAML file: ")
+		newPath := prompt(reader, "Enter the path to the changed Swagger YAML file: ")
+		if err := operationIDReport(oldPath, newPath); err != nil {
+			return fmt.Errorf("comparing operationIds: %w", err)
+		}
+	case "patch":
+		filePath, err := promptSpecFile(reader, "Enter the path to the Swagger YAML file", true)
+		if err != nil {
+			return err
+		}
+		pointer := prompt(reader, "Enter the JSON pointer to set (e.g., /paths/~1pets/get/summary): ")
+		value := prompt(reader, "Enter the new value as YAML or JSON: ")
+		if err := patchSwagger(filePath, pointer, value); err != nil {
+			return fmt.Errorf("patching Swagger file: %w", err)
+		}
+	case "rename-schema":
+		filePath, err := promptSpecFile(reader, "Enter the path to the Swagger YAML file", true)
+		if err != nil {
+			return err
+		}
+		oldName := prompt(reader, "Enter the current component schema name: ")
+		newName := prompt(reader, "Enter the new component schema name: ")
+		if err := renameSchema(filePath, oldName, newName); err != nil {
+			return fmt.Errorf("renaming schema: %w", err)
+		}
+	case "resolve-allof":
+		filePath, err := promptSpecFile(reader, "Enter the path to the Swagger YAML file", true)
+		if err != nil {
+			return err
+		}
+		name := prompt(reader, "Enter the component schema name: ")
+		if err := resolveAllOfComponent(filePath, name); err != nil {
+			return fmt.Errorf("resolving allOf: %w", err)
+		}
+	case "serve":
+		filePath, err := promptSpecFile(reader, "Enter the path to the Swagger YAML file", true)
+		if err != nil {
+			return err
+		}
+		if err := serveSwagger(filePath); err != nil {
+			return fmt.Errorf("serving Swagger file: %w", err)
+		}
+	case "set-info":
+		filePath, err := promptSpecFile(reader, "Enter the path to the Swagger YAML file", true)
+		if err != nil {
+			return err
+		}
+		if err := setInfo(filePath, reader); err != nil {
+			return fmt.Errorf("setting Swagger info: %w", err)
+		}
+	case "set-servers":
+		filePath, err := promptSpecFile(reader, "Enter the path to the Swagger YAML file", true)
+		if err != nil {
+			return err
+		}
+		profile := *serverProfile
+		if profile == "" {
+			profile = prompt(reader, "Enter the server profile to apply (e.g., prod): ")
+		}
+		if err := setServers(filePath, profile); err != nil {
+			return fmt.Errorf("setting servers: %w", err)
+		}
+	case "test-response":
+		filePath, err := promptSpecFile(reader, "Enter the path to the Swagger YAML file", true)
+		if err != nil {
+			return err
+		}
+		path := prompt(reader, "Enter the path of the operation (e.g., /pets): ")
+		method := strings.ToLower(prompt(reader, "Enter HTTP method (get/post/put/delete): "))
+		status := prompt(reader, "Enter the response status code (blank for "+*responseStatus+"): ")
+		if status == "" {
+			status = *responseStatus
+		}
+		samplePath := prompt(reader, "Enter the path to the JSON sample: ")
+		if err := testResponse(filePath, path, method, status, samplePath); err != nil {
+			return fmt.Errorf("testing response: %w", err)
+		}
+	case "validate":
+		filePath, err := promptSpecFile(reader, "Enter the path to the Swagger YAML file", true)
+		if err != nil {
+			return err
+		}
+		if err := validateSwaggerFile(filePath); err != nil {
+			return fmt.Errorf("validating Swagger file: %w", err)
+		}
+	case "validate-dir":
+		dir := prompt(reader, "Enter the directory to validate: ")
+		pattern := prompt(reader, "Enter the file name pattern (blank for *.yaml): ")
+		if pattern == "" {
+			pattern = "*.yaml"
+		}
+		if err := validateDir(dir, pattern); err != nil {
+			return fmt.Errorf("validating directory: %w", err)
+		}
+	default:
+		return fmt.Errorf("invalid action %q. Please enter %s", action, formatActionList())
+	}
+	return nil
+}
+
+// Prompt for the path of the spec file an action works on. The -file flag
+// answers the prompt when given; otherwise a blank answer falls back to the
+// SWAGGER_FILE environment variable. Explicit input always wins. When
+// mustExist is set, a file taken from the variable has to exist.
+func promptSpecFile(reader *bufio.Reader, label string, mustExist bool) (string, error) {
+	if *specFile != "" {
+		return *specFile, nil
+	}
+
+	envFile := os.Getenv("SWAGGER_FILE")
+	if envFile == "" {
+		return prompt(reader, label+": "), nil
+	}
+
+	filePath := prompt(reader, fmt.Sprintf("%s (blank for %s): ", label, envFile))
+	if filePath != "" {
+		return filePath, nil
+	}
+	if mustExist {
+		statPath := envFile
+		if archivePath, _, ok := splitArchivePath(envFile); ok {
+			statPath = archivePath
+		}
+		if _, err := os.Stat(statPath); err != nil {
+			return "", fmt.Errorf("SWAGGER_FILE is set to %s, which can't be read: %w", envFile, err)
+		}
+	}
+	return envFile, nil
+}
+
+// Prompt the user for a line of input and return it trimmed. Prompts go to
+// stderr so they don't end up in output piped from stdout.
+func prompt(reader *bufio.Reader, label string) string {
+	input, _ := promptLine(reader, label)
+	return input
+}
+
+// Prompt like prompt, but return io.EOF when input has ended without a
+// line, for loops that need to stop instead of reading blanks forever
+func promptLine(reader *bufio.Reader, label string) (string, error) {
+	fmt.Fprint(os.Stderr, label)
+	input, err := reader.ReadString('\n')
+	if err == io.EOF && input == "" {
+		return "", io.EOF
 	}
+	return strings.TrimSpace(input), nil
 }
 
 // View an existing Swagger YAML file
 func viewSwagger(filePath string) error {
-	data, err := ioutil.ReadFile(filePath)
+	data, err := readSpecData(filePath)
 	if err != nil {
 		return err
 	}
@@ -97,160 +806,746 @@ func viewSwagger(filePath string) error {
 	return nil
 }
 
-// Create a new Swagger YAML file with a basic structure
+// Create a new Swagger YAML file with a basic structure, or from the
+// -template file when one is given. The -title, -version, -desc, -terms
+// and -summary flags override the corresponding Info entries either way.
+// An existing file is only overwritten with -force.
 func createSwagger(filePath string) error {
-	swagger := SwaggerTemplate{
+	target := outputFile(filePath)
+	if target != "-" && !*forceCreate {
+		if _, err := os.Stat(target); err == nil {
+			return fmt.Errorf("%s already exists; use update to change it, or -force to overwrite it", target)
+		}
+	}
+
+	swagger := newSwaggerTemplate()
+	if *templatePath != "" {
+		template, err := readSwaggerFile(*templatePath)
+		if err != nil {
+			return fmt.Errorf("reading template %s: %w", *templatePath, err)
+		}
+		swagger = template
+		if swagger.OpenAPI == "" {
+			swagger.OpenAPI = "3.0.3"
+		}
+		if swagger.Info == nil {
+			swagger.Info = make(map[string]interface{})
+		}
+		if swagger.Paths == nil {
+			swagger.Paths = make(map[string]PathItem)
+		}
+	}
+
+	if err := applyInfoUpdates(swagger, infoFlagUpdates()); err != nil {
+		return err
+	}
+
+	return writeSwaggerFile(target, swagger)
+}
+
+// Build the minimal structure every new spec starts from
+func newSwaggerTemplate() *SwaggerTemplate {
+	return &SwaggerTemplate{
 		OpenAPI: "3.0.3",
 		Info: map[string]interface{}{
 			"title":       "New API",
 			"description": "This is a newly created Swagger API",
 			"version":     "1.0.0",
 		},
-		Paths: make(map[string]map[string]Operation),
+		Paths: make(map[string]PathItem),
 	}
-
-	return writeSwaggerFile(filePath, &swagger)
 }
 
 // Update an existing Swagger YAML file
 func updateSwagger(filePath string, reader *bufio.Reader) error {
+	unlock, err := lockFile(filePath)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
 	// Read existing Swagger YAML file
 	swagger, err := readSwaggerFile(filePath)
 	if err != nil {
 		return err
 	}
+	inferenceWarnings = nil
+	mediaType, err := parseContentType(*contentType)
+	if err != nil {
+		return err
+	}
+	status := *responseStatus
+	if !statusCodePattern.MatchString(status) {
+		return fmt.Errorf("invalid status code %q", status)
+	}
+	if *detectRecursion && !*extractComponent {
+		return fmt.Errorf("-detect-recursion needs -extract-component")
+	}
 
 	// Adding or updating an existing path based on user input
-	fmt.Print("Enter the path to add/update (e.g., /pets): ")
+	fmt.Fprint(os.Stderr, "Enter the path to add/update (e.g., /pets): ")
 	path, _ := reader.ReadString('\n')
 	path = strings.TrimSpace(path)
 
-	fmt.Print("Enter HTTP method (get/post/put/delete): ")
+	fmt.Fprint(os.Stderr, "Enter HTTP method (get/post/put/delete): ")
 	method, _ := reader.ReadString('\n')
 	method = strings.ToLower(strings.TrimSpace(method))
 
-	// Prompt user to provide JSON response as a string or a file path
-	fmt.Print("Enter JSON response directly or type 'file' to provide a file path: ")
-	inputType, _ := reader.ReadString('\n')
-	inputType = strings.TrimSpace(strings.ToLower(inputType))
+	// A 204 or 304 response has no body, so there's no sample to describe
+	if isBodilessStatus(status) && !*multipart {
+		if setOperationResponse(swagger, path, method, status, Response{Description: responseDescription(status)}) {
+			fmt.Fprintln(os.Stderr, "Creating a new operation...")
+		} else {
+			fmt.Fprintln(os.Stderr, "Updating the existing operation response...")
+		}
+		return writeSwaggerFile(outputFile(filePath), swagger)
+	}
+
+	// Prompt user to provide JSON response as a string or a file path, or to
+	// define the schema by hand, unless -json gave the samples already
+	input := ""
+	if len(jsonSamples) == 0 && *yamlSample == "" {
+		fmt.Fprint(os.Stderr, "Enter JSON response directly, type 'file' to provide a file path, or 'manual' to define the schema by hand: ")
+		input, _ = reader.ReadString('\n')
+		input = strings.TrimSpace(input)
+	}
 
+	var schema Schema
 	var jsonData map[string]interface{}
+	if len(jsonSamples) > 0 {
+		if schema, jsonData, err = sampleFilesSchema(jsonSamples); err != nil {
+			return err
+		}
+	} else if strings.EqualFold(input, "manual") {
+		schema = buildSchemaManually(reader, "")
+	} else if *streamSamples && strings.EqualFold(input, "file") {
+		if schema, err = readStreamedSampleSchema(reader); err != nil {
+			return err
+		}
+	} else {
+		var raw []byte
+		if *yamlSample != "" {
+			jsonData, raw, err = readYAMLSample(*yamlSample)
+		} else {
+			jsonData, raw, err = readJSONSample(input, reader)
+		}
+		if err != nil {
+			return err
+		}
 
-	if inputType == "file" {
-		// User wants to provide a file path
-		fmt.Print("Enter the JSON file path: ")
-		jsonFilePath, _ := reader.ReadString('\n')
-		jsonFilePath = strings.TrimSpace(jsonFilePath)
+		// Generate the schema from JSON
+		schema, err = generateSchema(jsonData)
+		if err != nil {
+			return err
+		}
+		applyArrayBounds(&schema, []interface{}{jsonData})
+		if !*sortProperties {
+			applyPropertyOrder(&schema, jsonKeyOrder(raw), "")
+		}
+	}
 
-		fileData, err := ioutil.ReadFile(jsonFilePath)
+	// Overrides take precedence over whatever was inferred from samples
+	if !strings.EqualFold(input, "manual") {
+		overrides, err := loadTypeOverrides()
 		if err != nil {
 			return err
 		}
+		applyTypeOverrides(&schema, overrides)
+	}
+
+	// With -Werror, inference warnings fail the update once the file has
+	// been written, or before writing it with -Werror-no-write
+	warningsErr := inferenceWarningsError()
+	if warningsErr != nil && *werrorNoWrite {
+		return warningsErr
+	}
+
+	// With -multipart the sample describes form fields of the request body
+	if *multipart {
+		body := multipartRequestBody(schema)
+		body.Required = promptBodyRequired(reader, method)
+		created := setOperationRequestBody(swagger, path, method, body)
+		storeSample(swagger, path, method, jsonData)
+		if created {
+			fmt.Fprintln(os.Stderr, "Creating a new operation...")
+		} else {
+			fmt.Fprintln(os.Stderr, "Updating the existing operation request body...")
+		}
+		if err := writeSwaggerFile(outputFile(filePath), swagger); err != nil {
+			return err
+		}
+		return warningsErr
+	}
+
+	// With -problem-details, error responses get the RFC 7807 problem
+	// details shape, keeping any extra fields from the sample
+	if *problemDetails && isErrorStatus(status) {
+		schema = problemDetailsSchema(schema)
+		mediaType = problemJSONMediaType
+	}
 
-		err = json.Unmarshal(fileData, &jsonData)
+	// With -extract-component the schema is stored under components and
+	// the response refers to it
+	if *extractComponent {
+		if !*detectRecursion {
+			schema = extractNestedComponents(swagger, schema)
+		}
+		schema, err = extractResponseComponent(swagger, path, method, status, mediaType, schema, reader)
 		if err != nil {
 			return err
 		}
+		// Recursion is detected once the response component has a name, so
+		// that nested copies of the response itself can refer to it
+		if *detectRecursion {
+			name := strings.TrimPrefix(schema.Ref, schemaRefPrefix)
+			extractRecursiveComponents(swagger, name)
+			swagger.Components.Schemas[name] = extractNestedComponents(swagger, swagger.Components.Schemas[name])
+		}
+	}
+
+	// Update the existing operation or create a new one. Server-sent
+	// events have no array wrapper, so the schema describes a single event.
+	media := MediaType{Schema: schema}
+	if *responseExample && jsonData != nil {
+		media.Example = sampleValue(jsonData)
+	}
+	response := Response{
+		Description: responseDescription(status),
+		Content: map[string]MediaType{
+			mediaType: media,
+		},
+	}
+	if mediaType == "text/event-stream" {
+		response.Description = "Stream of server-sent events"
+	}
+	created := setOperationResponse(swagger, path, method, status, response)
+	storeSample(swagger, path, method, jsonData)
+	if created {
+		fmt.Fprintln(os.Stderr, "Creating a new operation...")
 	} else {
-		// User provides JSON directly
-		err = json.Unmarshal([]byte(inputType), &jsonData)
+		fmt.Fprintln(os.Stderr, "Updating the existing operation response...")
+	}
+
+	// Write the updated Swagger YAML back to the file
+	if err := writeSwaggerFile(outputFile(filePath), swagger); err != nil {
+		return err
+	}
+	return warningsErr
+}
+
+// With -keep-sample, store the sample a schema was inferred from in the
+// x-sample extension of its operation, replacing any earlier one. Schemas
+// defined by hand have no sample, so nothing is stored for them.
+func storeSample(swagger *SwaggerTemplate, path, method string, sample map[string]interface{}) {
+	if !*keepSample || sample == nil {
+		return
+	}
+	operation := swagger.Paths[path].Operations[method]
+	if operation.Extensions == nil {
+		operation.Extensions = make(map[string]interface{})
+	}
+	operation.Extensions["x-sample"] = sampleValue(sample)
+	swagger.Paths[path].Operations[method] = operation
+}
+
+// Read a JSON sample object given directly as input, or from a file when
+// the input is 'file'. The raw JSON is returned alongside the decoded object.
+func readJSONSample(input string, reader *bufio.Reader) (map[string]interface{}, []byte, error) {
+	// User provides JSON directly
+	raw := []byte(input)
+
+	if strings.EqualFold(input, "file") {
+		// User wants to provide a file path
+		fmt.Fprint(os.Stderr, "Enter the JSON file path: ")
+		jsonFilePath, _ := reader.ReadString('\n')
+		jsonFilePath = strings.TrimSpace(jsonFilePath)
+
+		fileData, err := ioutil.ReadFile(jsonFilePath)
 		if err != nil {
-			return err
+			return nil, nil, err
 		}
+		raw = fileData
 	}
 
-	// Generate the schema from JSON
-	schema := generateSchema(jsonData)
+	var jsonData map[string]interface{}
+	if err := decodeJSON(raw, &jsonData); err != nil {
+		return nil, nil, err
+	}
+	return jsonData, raw, nil
+}
 
-	// Check if the path and method already exist
-	if swagger.Paths == nil {
-		swagger.Paths = make(map[string]map[string]Operation)
+// Read a YAML sample object as if it were JSON. It is re-encoded as JSON
+// in its own key order, so it is inferred exactly like the equivalent JSON
+// sample, and is returned both decoded and as that JSON.
+func readYAMLSample(filename string) (map[string]interface{}, []byte, error) {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, nil, err
+	}
+	var document yaml.MapSlice
+	if err := yaml.Unmarshal(data, &document); err != nil {
+		return nil, nil, fmt.Errorf("%s: %w", filename, err)
 	}
-	if swagger.Paths[path] == nil {
-		swagger.Paths[path] = make(map[string]Operation)
+	if err := checkStringKeys(document, ""); err != nil {
+		return nil, nil, fmt.Errorf("%s: %w", filename, err)
+	}
+
+	var buf bytes.Buffer
+	if err := writeOrderedJSON(&buf, document); err != nil {
+		return nil, nil, err
+	}
+	var jsonData map[string]interface{}
+	if err := decodeJSON(buf.Bytes(), &jsonData); err != nil {
+		return nil, nil, fmt.Errorf("%s: %w", filename, err)
 	}
+	return jsonData, buf.Bytes(), nil
+}
 
-	// Update the existing operation or create a new one
-	if existingOperation, ok := swagger.Paths[path][method]; ok {
-		// If operation already exists, update the response
-		fmt.Println("Updating the existing operation response...")
-		existingOperation.Responses["200"] = Response{
-			Description: "Successful response",
-			Content: map[string]MediaType{
-				"application/json": {
-					Schema: schema,
-				},
-			},
+// Fail on mapping keys in a decoded YAML value that aren't strings, such
+// as numbers or booleans, which JSON samples can't have
+func checkStringKeys(value interface{}, path string) error {
+	switch v := value.(type) {
+	case yaml.MapSlice:
+		for _, item := range v {
+			key, ok := item.Key.(string)
+			if !ok {
+				return fmt.Errorf("%s: unsupported non-string key %v", displayKeyPath(path), item.Key)
+			}
+			if err := checkStringKeys(item.Value, joinKeyPath(path, key)); err != nil {
+				return err
+			}
 		}
-		swagger.Paths[path][method] = existingOperation
-	} else {
-		// Create a new operation if it does not exist
-		fmt.Println("Creating a new operation...")
-		newOperation := Operation{
+	case []interface{}:
+		for _, item := range v {
+			if err := checkStringKeys(item, path+"[]"); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Validate a response content type and return it in canonical lowercase form
+func parseContentType(value string) (string, error) {
+	mediaType, params, err := mime.ParseMediaType(value)
+	if err != nil {
+		return "", fmt.Errorf("invalid content type %q: %w", value, err)
+	}
+	if !strings.Contains(mediaType, "/") {
+		return "", fmt.Errorf("invalid content type %q: expected type/subtype", value)
+	}
+	if len(params) > 0 {
+		return mime.FormatMediaType(mediaType, params), nil
+	}
+	return mediaType, nil
+}
+
+// Report whether responses with a status have no body, so that they are
+// documented without content: 204 No Content and 304 Not Modified
+func isBodilessStatus(status string) bool {
+	return status == "204" || status == "304"
+}
+
+// The default description for a response with the given status code
+func responseDescription(status string) string {
+	if strings.HasPrefix(status, "2") {
+		return "Successful response"
+	}
+	if status == "default" {
+		return "Default response"
+	}
+	if code, err := strconv.Atoi(status); err == nil && http.StatusText(code) != "" {
+		return http.StatusText(code)
+	}
+	return "Error response"
+}
+
+// Look up the operation at path and method, creating the path and a sample
+// operation when they don't exist yet. Reports whether it was created.
+func ensureOperation(swagger *SwaggerTemplate, path, method string) (Operation, bool) {
+	ensurePathItem(swagger, path)
+
+	operation, exists := swagger.Paths[path].Operations[method]
+	if !exists {
+		operation = Operation{
 			Summary:     "Sample operation for " + path,
 			Description: "This is a sample description for the new operation.",
-			Responses: map[string]Response{
-				"200": {
-					Description: "Successful response",
-					Content: map[string]MediaType{
-						"application/json": {
-							Schema: schema,
-						},
-					},
-				},
-			},
 		}
-		swagger.Paths[path][method] = newOperation
+		swagger.Paths[path].Operations[method] = operation
 	}
+	return operation, !exists
+}
 
-	// Write the updated Swagger YAML back to the file
-	return writeSwaggerFile(filePath, swagger)
+// Create the path item for path, with an empty operations map, unless it
+// already has one
+func ensurePathItem(swagger *SwaggerTemplate, path string) {
+	if swagger.Paths == nil {
+		swagger.Paths = make(map[string]PathItem)
+	}
+	if item := swagger.Paths[path]; item.Operations == nil {
+		item.Operations = make(map[string]Operation)
+		swagger.Paths[path] = item
+	}
+}
+
+// Set the response for a status code on the operation at path and method,
+// creating the operation if needed. Reports whether it was created.
+func setOperationResponse(swagger *SwaggerTemplate, path, method, status string, response Response) bool {
+	operation, created := ensureOperation(swagger, path, method)
+	if operation.Responses == nil {
+		operation.Responses = make(map[string]Response)
+	}
+	// Regenerating a response keeps the links and vendor extensions set on it
+	if existing, ok := operation.Responses[status]; ok {
+		if response.Links == nil {
+			response.Links = existing.Links
+		}
+		if response.Extensions == nil {
+			response.Extensions = existing.Extensions
+		}
+	}
+	operation.Responses[status] = response
+	swagger.Paths[path].Operations[method] = operation
+	return created
 }
 
 // Generate a Swagger schema from a JSON object
-func generateSchema(data map[string]interface{}) Schema {
-	schema := Schema{Type: "object", Properties: make(map[string]Schema)}
+func generateSchema(data map[string]interface{}) (Schema, error) {
+	defer timePhase("generate", time.Now())
+	return inferSchema(data, "", 0)
+}
+
+// Infer the schema of a JSON value, recursing into objects and arrays.
+// path is the dotted key path of the value, used when reporting errors, and
+// depth is how many objects and arrays it is nested in.
+func inferSchema(value interface{}, path string, depth int) (Schema, error) {
+	// Past -max-depth, containers are described without their contents.
+	// Arrays still need items to be valid, so they allow any item.
+	if *maxDepth > 0 && depth >= *maxDepth {
+		switch value.(type) {
+		case map[string]interface{}:
+			return Schema{Type: "object"}, nil
+		case []interface{}:
+			return Schema{Type: "array", Items: &Schema{}}, nil
+		}
+	}
 
-	for key, value := range data {
-		fieldType := reflect.TypeOf(value).Kind()
-		propSchema := Schema{Type: getSwaggerType(fieldType)}
-		if fieldType == reflect.Map {
-			propSchema.Type = "object"
-		} else if fieldType == reflect.Slice {
-			propSchema.Type = "array"
+	switch v := value.(type) {
+	case map[string]interface{}:
+		schema := Schema{Type: "object", Properties: make(map[string]Schema)}
+		for key, prop := range v {
+			if prop == nil && *dropNulls {
+				continue
+			}
+			propSchema, err := inferSchema(prop, joinKeyPath(path, key), depth+1)
+			if err != nil {
+				return Schema{}, err
+			}
+			if *schemaTitles && propSchema.Type == "object" {
+				propSchema.Title = humanizeName(key)
+			}
+			schema.Properties[key] = propSchema
+		}
+		return schema, nil
+	case []interface{}:
+		if len(v) == 0 {
+			if *strictTypes {
+				return Schema{}, fmt.Errorf("cannot infer item type of empty array at %s", displayKeyPath(path))
+			}
+			warnInference(path, "empty array, item type defaulted to string")
+			return Schema{Type: "array", Items: &Schema{Type: "string"}}, nil
+		}
+		items, err := inferSchema(v[0], path+"[]", depth+1)
+		if err != nil {
+			return Schema{}, err
 		}
-		schema.Properties[key] = propSchema
+		for _, element := range v[1:] {
+			elementSchema, err := inferSchema(element, path+"[]", depth+1)
+			if err != nil {
+				return Schema{}, err
+			}
+			items = mergeItemSchema(items, elementSchema, path+"[]")
+		}
+		schema := Schema{Type: "array", Items: &items}
+		if *inferUnique && len(v) > 1 && distinctScalars(v) {
+			schema.UniqueItems = true
+		}
+		if *arrayExample {
+			items.Example = sampleValue(v[0])
+			if *arraySlice {
+				schema.Example = sampleValue(v[:min(len(v), 2)])
+			}
+		}
+		return schema, nil
 	}
 
+	if value == nil {
+		if *strictTypes {
+			return Schema{}, fmt.Errorf("cannot infer type of %s: value is null", displayKeyPath(path))
+		}
+		warnInference(path, "value is null, type defaulted to string")
+		return Schema{Type: "string", Nullable: true, nullSample: true}, nil
+	}
+
+	schemaType, format, ok := SchemaTypeMapper.MapType(path, value)
+	if !ok {
+		kind := reflect.ValueOf(value).Kind()
+		if *strictTypes {
+			return Schema{}, fmt.Errorf("cannot infer type of %s: unsupported kind %s", displayKeyPath(path), kind)
+		}
+		warnInference(path, fmt.Sprintf("unsupported kind %s, type defaulted to string", kind))
+		schemaType, format = "string", ""
+	}
+	return Schema{Type: schemaType, Format: format}, nil
+}
+
+// Merge the schema of one more array element into the item schema inferred
+// from the elements before it. Arrays read with -stream are merged the same
+// way, so both give the same schema. Type conflicts between elements are
+// inference warnings.
+func mergeItemSchema(items, element Schema, path string) Schema {
+	return mergeSchemas(items, element, path, recordInferenceWarning)
+}
+
+// Report whether a sample string is an absolute URL such as
+// https://example.com/pets. url.ParseRequestURI also accepts bare paths and
+// text like "note:done", so a scheme and a host are both required, and
+// strings with whitespace are left alone.
+func looksLikeURI(value string) bool {
+	if strings.ContainsAny(value, " \t\r\n") {
+		return false
+	}
+	u, err := url.ParseRequestURI(value)
+	return err == nil && u.Scheme != "" && u.Host != ""
+}
+
+// Infer the schema of a JSON number from its literal text, so that large
+// integer IDs are classified without going through float64
+func numberSchema(n json.Number) Schema {
+	literal := n.String()
+	if strings.ContainsAny(literal, ".eE") {
+		return Schema{Type: "number"}
+	}
+
+	schema := Schema{Type: "integer"}
+	if *intFormat {
+		// Integers too large even for int64 still need the wider format
+		if _, err := strconv.ParseInt(literal, 10, 32); err == nil {
+			schema.Format = "int32"
+		} else {
+			schema.Format = "int64"
+		}
+	}
 	return schema
 }
 
-// Get Swagger-compatible type from Go's reflect kind
-func getSwaggerType(kind reflect.Kind) string {
+// Turn a camelCase or snake_case property name into words for display,
+// e.g. firstName and first_name both become "First Name"
+func humanizeName(name string) string {
+	var words []string
+	var word []rune
+	runes := []rune(name)
+	for i, r := range runes {
+		switch {
+		case r == '_' || r == '-' || r == ' ' || r == '.':
+			if len(word) > 0 {
+				words = append(words, string(word))
+				word = nil
+			}
+			continue
+		case unicode.IsUpper(r) && len(word) > 0:
+			// Start a new word at a lower-to-upper change, or at the last
+			// capital of an acronym followed by a lowercase letter (HTTPServer)
+			prev := runes[i-1]
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextLower) {
+				words = append(words, string(word))
+				word = nil
+			}
+		}
+		word = append(word, r)
+	}
+	if len(word) > 0 {
+		words = append(words, string(word))
+	}
+
+	for i, w := range words {
+		words[i] = capitalize(w)
+	}
+	return strings.Join(words, " ")
+}
+
+// Report whether every element of an array is a scalar and no two are equal
+func distinctScalars(values []interface{}) bool {
+	seen := make(map[interface{}]bool, len(values))
+	for _, value := range values {
+		switch value.(type) {
+		case map[string]interface{}, []interface{}:
+			return false
+		}
+		if seen[value] {
+			return false
+		}
+		seen[value] = true
+	}
+	return true
+}
+
+// Join a parent key path and a property name with a dot
+func joinKeyPath(parent, key string) string {
+	if parent == "" {
+		return key
+	}
+	return parent + "." + key
+}
+
+// Format a key path for messages, naming the root value explicitly
+func displayKeyPath(path string) string {
+	if path == "" {
+		return "(root)"
+	}
+	return path
+}
+
+// Get Swagger-compatible type from Go's reflect kind. The boolean is false
+// when the kind has no clean mapping and "string" was used as a fallback.
+func getSwaggerType(kind reflect.Kind) (string, bool) {
 	switch kind {
 	case reflect.String:
-		return "string"
+		return "string", true
 	case reflect.Int, reflect.Int32, reflect.Int64:
-		return "integer"
+		return "integer", true
 	case reflect.Float32, reflect.Float64:
-		return "number"
+		return "number", true
 	case reflect.Bool:
-		return "boolean"
+		return "boolean", true
 	case reflect.Slice:
-		return "array"
+		return "array", true
 	case reflect.Map:
-		return "object"
+		return "object", true
+	default:
+		return "string", false
+	}
+}
+
+// Decode JSON sample data, keeping numbers as json.Number so integers
+// and floats can be told apart without losing precision
+func decodeJSON(data []byte, v interface{}) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	return decoder.Decode(v)
+}
+
+// Convert decoded JSON sample data into plain Go values for storing in the
+// spec, turning each json.Number into an int64 or float64 so it is written
+// as a number rather than a string
+func sampleValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		plain := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			plain[key] = sampleValue(item)
+		}
+		return plain
+	case []interface{}:
+		plain := make([]interface{}, len(v))
+		for i, item := range v {
+			plain[i] = sampleValue(item)
+		}
+		return plain
+	case json.Number:
+		if n, err := v.Int64(); err == nil {
+			return n
+		}
+		if f, err := v.Float64(); err == nil {
+			return f
+		}
+		return v.String()
+	default:
+		return value
+	}
+}
+
+// Convert a value decoded by yaml.v2 into the shape encoding/json produces,
+// turning map[interface{}]interface{} into map[string]interface{} all the
+// way down. Fails on mapping keys that aren't strings.
+func normalizeYAMLValue(value interface{}) (interface{}, error) {
+	switch v := value.(type) {
+	case map[interface{}]interface{}:
+		normalized := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			name, ok := key.(string)
+			if !ok {
+				return nil, fmt.Errorf("unsupported non-string key %v", key)
+			}
+			value, err := normalizeYAMLValue(item)
+			if err != nil {
+				return nil, err
+			}
+			normalized[name] = value
+		}
+		return normalized, nil
+	case map[string]interface{}:
+		normalized := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			value, err := normalizeYAMLValue(item)
+			if err != nil {
+				return nil, err
+			}
+			normalized[key] = value
+		}
+		return normalized, nil
+	case []interface{}:
+		normalized := make([]interface{}, len(v))
+		for i, item := range v {
+			value, err := normalizeYAMLValue(item)
+			if err != nil {
+				return nil, err
+			}
+			normalized[i] = value
+		}
+		return normalized, nil
 	default:
-		return "string"
+		return value, nil
 	}
 }
 
-// Read an existing Swagger YAML file
+// Read an existing Swagger YAML or JSON file, which may be inside an
+// archive (see readSpecData)
 func readSwaggerFile(filename string) (*SwaggerTemplate, error) {
-	data, err := ioutil.ReadFile(filename)
+	defer timePhase("parse", time.Now())
+
+	data, err := readSpecData(filename)
 	if err != nil {
 		return nil, err
 	}
+	return ReadSwagger(bytes.NewReader(data))
+}
+
+// ReadSwagger parses a Swagger YAML or JSON document from r, so specs can
+// come from buffers, network streams or an embedded FS as well as files
+func ReadSwagger(r io.Reader) (*SwaggerTemplate, error) {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	// JSON is valid YAML, but yaml.v2 doesn't handle every JSON document
+	// faithfully, so anything that looks like JSON goes through
+	// encoding/json first regardless of the file extension
+	if bytes.HasPrefix(bytes.TrimSpace(data), []byte("{")) {
+		var document interface{}
+		if err := decodeJSON(data, &document); err != nil {
+			return nil, err
+		}
+		if data, err = yaml.Marshal(sampleValue(document)); err != nil {
+			return nil, err
+		}
+	} else if *strictYAML {
+		if err := checkDuplicateKeys(data); err != nil {
+			return nil, err
+		}
+	}
 
 	var swagger SwaggerTemplate
 	err = yaml.Unmarshal(data, &swagger)
@@ -261,12 +1556,71 @@ func readSwaggerFile(filename string) (*SwaggerTemplate, error) {
 	return &swagger, nil
 }
 
-// Write Swagger YAML file
+// WriteSwagger writes a spec to w as YAML
+func WriteSwagger(w io.Writer, swagger *SwaggerTemplate) error {
+	data, err := marshalSwagger(swagger, false)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(data)
+	return err
+}
+
+// Fail an import that added more paths than -limit-paths allows, given how
+// many paths the spec had before it
+func checkPathLimit(swagger *SwaggerTemplate, before int) error {
+	added := len(swagger.Paths) - before
+	if *limitPaths > 0 && added > *limitPaths {
+		return fmt.Errorf("import would add %d paths, more than the -limit-paths limit of %d; nothing was written", added, *limitPaths)
+	}
+	return nil
+}
+
+// The file an editing action should write to: the -o path when given,
+// otherwise the file it read from
+func outputFile(filePath string) string {
+	if outputPath != "" {
+		return outputPath
+	}
+	return filePath
+}
+
+// Write Swagger YAML file, or JSON when the file name ends in .json, or
+// YAML to stdout when it is -. -minify makes JSON compact, and - JSON too.
+// With -strict, invalid specs aren't written, and with -max-size neither
+// are those over the budget.
 func writeSwaggerFile(filename string, swagger *SwaggerTemplate) error {
-	data, err := yaml.Marshal(swagger)
+	defer timePhase("write", time.Now())
+
+	fixNullableSchemas(swagger)
+	if *strictMode {
+		if problems := validateSwagger(swagger); len(problems) > 0 {
+			return fmt.Errorf("not writing %s, it has %d problems:\n  %s", filename, len(problems), strings.Join(problems, "\n  "))
+		}
+	}
+
+	// -minify only applies to JSON, so it makes - write JSON as well
+	asJSON := strings.EqualFold(filepath.Ext(filename), ".json") || (filename == "-" && *minifyOutput)
+	data, err := marshalSwagger(swagger, asJSON)
 	if err != nil {
 		return err
 	}
+	if asJSON && *minifyOutput {
+		var compact bytes.Buffer
+		if err := json.Compact(&compact, data); err != nil {
+			return err
+		}
+		data = compact.Bytes()
+	}
+	if err := checkSizeBudget(filename, data); err != nil {
+		return err
+	}
+
+	// A path of - writes the YAML to stdout, with nothing else mixed in
+	if filename == "-" {
+		_, err := os.Stdout.Write(data)
+		return err
+	}
 
 	err = ioutil.WriteFile(filename, data, 0644)
 	if err != nil {
@@ -0,0 +1,818 @@
+package main
+
+import (
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v2"
+)
+
+// Set a flag for the duration of a test
+func setFlag[T any](t *testing.T, flag *T, value T) {
+	t.Helper()
+	old := *flag
+	*flag = value
+	t.Cleanup(func() { *flag = old })
+}
+
+// Decode a JSON sample and infer its schema
+func sampleSchema(t *testing.T, sample string) Schema {
+	t.Helper()
+	var data map[string]interface{}
+	if err := decodeJSON([]byte(sample), &data); err != nil {
+		t.Fatalf("decoding %s: %v", sample, err)
+	}
+	schema, err := generateSchema(data)
+	if err != nil {
+		t.Fatalf("inferring %s: %v", sample, err)
+	}
+	return schema
+}
+
+func TestIntFormat(t *testing.T) {
+	setFlag(t, intFormat, true)
+	tests := []struct {
+		value      string
+		schemaType string
+		format     string
+	}{
+		{"0", "integer", "int32"},
+		{"-2147483648", "integer", "int32"},
+		{"2147483647", "integer", "int32"},
+		{"2147483648", "integer", "int64"},
+		{"-2147483649", "integer", "int64"},
+		{"9007199254740993", "integer", "int64"},
+		{"1.5", "number", ""},
+		{"1e3", "number", ""},
+	}
+	for _, test := range tests {
+		prop := sampleSchema(t, `{"n": `+test.value+`}`).Properties["n"]
+		if prop.Type != test.schemaType || prop.Format != test.format {
+			t.Errorf("%s: got %s/%s, want %s/%s", test.value, prop.Type, prop.Format, test.schemaType, test.format)
+		}
+	}
+}
+
+func TestLargeIntegerSamplesKeepPrecision(t *testing.T) {
+	// 2^53 + 1 can't be represented as a float64
+	var data map[string]interface{}
+	if err := decodeJSON([]byte(`{"id": 9007199254740993}`), &data); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := sampleValue(data), map[string]interface{}{"id": int64(9007199254740993)}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %#v, want %#v", got, want)
+	}
+}
+
+func TestIsFileFieldName(t *testing.T) {
+	tests := []struct {
+		name string
+		want bool
+	}{
+		{"file", true},
+		{"File", true},
+		{"user_file", true},
+		{"userFile", true},
+		{"avatar", true},
+		{"profileImage", true},
+		{"cover-photo", true},
+		{"profile", false},
+		{"myfile", false},
+		{"prefile", false},
+		{"name", false},
+	}
+	for _, test := range tests {
+		if got := isFileFieldName(test.name); got != test.want {
+			t.Errorf("isFileFieldName(%q) = %v, want %v", test.name, got, test.want)
+		}
+	}
+}
+
+func TestRequiredKeysWrittenWhenEmpty(t *testing.T) {
+	tests := []struct {
+		name  string
+		value interface{}
+		want  string
+	}{
+		{"operation without responses", Operation{Summary: "List pets"}, "responses: {}"},
+		{"operation with empty responses", Operation{Responses: map[string]Response{}}, "responses: {}"},
+		{"response without description", Response{}, `description: ""`},
+		{"request body without content", RequestBody{}, "content: {}"},
+	}
+	for _, test := range tests {
+		data, err := yaml.Marshal(test.value)
+		if err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		if !strings.Contains(string(data), test.want) {
+			t.Errorf("%s: got\n%s\nwant it to contain %s", test.name, data, test.want)
+		}
+	}
+}
+
+func TestCoerceBools(t *testing.T) {
+	tests := []struct {
+		value  string
+		coerce bool
+		want   string
+	}{
+		{`"true"`, true, "boolean"},
+		{`"false"`, true, "boolean"},
+		{`"True"`, true, "string"},
+		{`"FALSE"`, true, "string"},
+		{`"yes"`, true, "string"},
+		{`"1"`, true, "string"},
+		{`"true"`, false, "string"},
+		{`true`, false, "boolean"},
+	}
+	for _, test := range tests {
+		setFlag(t, coerceBools, test.coerce)
+		if got := sampleSchema(t, `{"flag": `+test.value+`}`).Properties["flag"].Type; got != test.want {
+			t.Errorf("%s with -coerce-bools=%v: got %s, want %s", test.value, test.coerce, got, test.want)
+		}
+	}
+}
+
+func TestOperationExtensionsRoundTrip(t *testing.T) {
+	spec := `openapi: 3.0.3
+info: {title: Pets, version: "1"}
+paths:
+  /pets:
+    get:
+      x-ratelimit: {limit: 100, window: 1m}
+      parameters:
+      - name: limit
+        in: query
+        x-example-values: [10, 50]
+        schema: {type: integer, x-unit: items}
+      responses:
+        "200":
+          description: ok
+          x-cache-ttl: 60
+          content:
+            application/json:
+              x-sample-source: prod
+              schema: {$ref: '#/components/schemas/Pet'}
+components:
+  x-owner: pets-team
+  schemas:
+    Pet:
+      type: object
+      x-internal: true
+      properties:
+        name: {type: string, x-pii: true}
+`
+	swagger, err := ReadSwagger(strings.NewReader(spec))
+	if err != nil {
+		t.Fatal(err)
+	}
+	setOperationResponse(swagger, "/owners", "get", "200", Response{Description: "ok"})
+	data, err := marshalSwagger(swagger, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	swagger, err = ReadSwagger(strings.NewReader(string(data)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	operation := swagger.Paths["/pets"].Operations["get"]
+	if got, want := operation.Extensions["x-ratelimit"], (map[interface{}]interface{}{"limit": 100, "window": "1m"}); !reflect.DeepEqual(got, want) {
+		t.Errorf("x-ratelimit: got %#v, want %#v", got, want)
+	}
+	if got := operation.Responses["200"].Extensions["x-cache-ttl"]; got != 60 {
+		t.Errorf("x-cache-ttl: got %#v, want 60", got)
+	}
+	if _, ok := swagger.Paths["/owners"].Operations["get"]; !ok {
+		t.Error("the added operation is missing")
+	}
+
+	param := operation.Parameters[0]
+	if got, want := param.Extensions["x-example-values"], []interface{}{10, 50}; !reflect.DeepEqual(got, want) {
+		t.Errorf("parameter x-example-values: got %#v, want %#v", got, want)
+	}
+	if got := param.Schema.Extensions["x-unit"]; got != "items" {
+		t.Errorf("parameter schema x-unit: got %#v, want items", got)
+	}
+	if got := operation.Responses["200"].Content["application/json"].Extensions["x-sample-source"]; got != "prod" {
+		t.Errorf("media type x-sample-source: got %#v, want prod", got)
+	}
+	pet := swagger.Components.Schemas["Pet"]
+	if pet.Extensions["x-internal"] != true || pet.Properties["name"].Extensions["x-pii"] != true {
+		t.Errorf("schema extensions: got %#v and %#v", pet.Extensions, pet.Properties["name"].Extensions)
+	}
+	if got := swagger.Components.Extensions["x-owner"]; got != "pets-team" {
+		t.Errorf("components x-owner: got %#v, want pets-team", got)
+	}
+	if problems := validateSwagger(swagger); len(problems) > 0 {
+		t.Errorf("got problems %q, want none", problems)
+	}
+}
+
+func TestValidateNestedExtensions(t *testing.T) {
+	spec := `openapi: 3.0.3
+info: {title: Pets, version: "1"}
+paths:
+  /pets:
+    get:
+      parameters:
+      - {name: limit, in: query, stlye: form}
+      responses:
+        "200":
+          description: ok
+          content:
+            application/json:
+              exmaples: {}
+components:
+  securitySchemas: {}
+  schemas:
+    Pet: {type: string, patern: "^a"}
+`
+	swagger, err := ReadSwagger(strings.NewReader(spec))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"components.securitySchemas: unknown key, extensions must start with x-",
+		"paths./pets.get.parameters[0].stlye: unknown key, extensions must start with x-",
+		"paths./pets.get.responses.200.application/json.exmaples: unknown key, extensions must start with x-",
+		"components.schemas.Pet.patern: unknown key, extensions must start with x-",
+	}
+	if got := validateSwagger(swagger); !reflect.DeepEqual(got, want) {
+		t.Errorf("got problems %q, want %q", got, want)
+	}
+}
+
+func TestComponentSectionsRoundTrip(t *testing.T) {
+	spec := `openapi: 3.0.3
+info: {title: Pets, version: "1"}
+paths: {}
+components:
+  schemas:
+    Pet: {type: object}
+  securitySchemes:
+    bearer: {type: http, scheme: bearer}
+  responses:
+    NotFound: {description: not found}
+`
+	swagger, err := ReadSwagger(strings.NewReader(spec))
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := marshalSwagger(swagger, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	swagger, err = ReadSwagger(strings.NewReader(string(data)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	schemes, _ := swagger.Components.Extensions["securitySchemes"].(map[interface{}]interface{})
+	if got, want := schemes["bearer"], (map[interface{}]interface{}{"type": "http", "scheme": "bearer"}); !reflect.DeepEqual(got, want) {
+		t.Errorf("securitySchemes.bearer: got %#v, want %#v", got, want)
+	}
+	if _, ok := swagger.Components.Extensions["responses"]; !ok {
+		t.Error("components.responses is missing")
+	}
+
+	other := &SwaggerTemplate{Components: Components{Extensions: map[string]interface{}{
+		"securitySchemes": map[interface{}]interface{}{
+			"bearer": map[interface{}]interface{}{"type": "http", "scheme": "basic"},
+			"apiKey": map[interface{}]interface{}{"type": "apiKey", "in": "header", "name": "X-Key"},
+		},
+	}}}
+	conflicts := mergeSpecs(swagger, other)
+	if want := []string{"components.securitySchemes.bearer is defined differently in both files"}; !reflect.DeepEqual(conflicts, want) {
+		t.Errorf("got conflicts %q, want %q", conflicts, want)
+	}
+	if _, ok := schemes["apiKey"]; !ok {
+		t.Error("the merged security scheme is missing")
+	}
+}
+
+func TestCreateFromTemplateKeepsComponents(t *testing.T) {
+	dir := t.TempDir()
+	template := filepath.Join(dir, "template.yaml")
+	err := os.WriteFile(template, []byte(`openapi: 3.0.3
+info: {title: Shared, version: "1"}
+servers:
+- url: https://api.example.com
+paths: {}
+components:
+  securitySchemes:
+    bearer: {type: http, scheme: bearer}
+  responses:
+    Error: {description: error}
+`), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	setFlag(t, templatePath, template)
+	target := filepath.Join(dir, "api.yaml")
+	if err := createSwagger(target); err != nil {
+		t.Fatal(err)
+	}
+
+	swagger, err := readSwaggerFile(target)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(swagger.Servers) != 1 || swagger.Info["title"] != "Shared" {
+		t.Errorf("got servers %v and info %v, want the template's", swagger.Servers, swagger.Info)
+	}
+	for _, section := range []string{"securitySchemes", "responses"} {
+		if _, ok := swagger.Components.Extensions[section]; !ok {
+			t.Errorf("components.%s from the template is missing", section)
+		}
+	}
+}
+
+func TestCanonicalizeKeepsUnmodelledKeys(t *testing.T) {
+	spec := `openapi: 3.0.3
+info: {title: Pets, version: "1"}
+paths:
+  /pets/{id}:
+    get:
+      parameters:
+      - name: id
+        in: path
+        required: true
+        schema: {type: string, pattern: "^[a-z]+$", maxLength: 20}
+        style: simple
+        example: rex
+      - name: limit
+        in: query
+        schema: {type: integer, minimum: 1, maximum: 100}
+      responses:
+        "200":
+          description: ok
+          content:
+            application/json:
+              schema:
+                oneOf:
+                - $ref: '#/components/schemas/Pet'
+                - {type: string}
+              examples:
+                rex: {value: {id: rex}}
+components:
+  schemas:
+    Pet:
+      type: object
+      x-internal: true
+      properties:
+        id: {type: string, readOnly: true}
+        age: {type: number, exclusiveMinimum: true, minimum: 0}
+`
+	var want interface{}
+	if err := yaml.Unmarshal([]byte(spec), &want); err != nil {
+		t.Fatal(err)
+	}
+
+	// Both ways of writing schemas, as yaml.v2 does and through schemaFields
+	for _, sorted := range []bool{true, false} {
+		setFlag(t, sortProperties, sorted)
+		swagger, err := ReadSwagger(strings.NewReader(spec))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := canonicalizeSpec(swagger); err != nil {
+			t.Fatal(err)
+		}
+		data, err := marshalSwagger(swagger, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var got interface{}
+		if err := yaml.Unmarshal(data, &got); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("-sort-properties=%v: canonicalize changed the spec:\n%s", sorted, data)
+		}
+	}
+}
+
+func TestGroupByTagComments(t *testing.T) {
+	spec := `openapi: 3.0.3
+info: {title: Pets, version: "1"}
+paths:
+  /health:
+    get:
+      responses: {"200": {description: ok}}
+  /pets:
+    get:
+      tags: [pets]
+      parameters:
+      - {name: limit, in: query, schema: {type: integer}}
+      responses: {"200": {description: ok}}
+  /owners:
+    get:
+      tags: [owners]
+      responses: {"200": {description: ok}}
+  /pets/{id}:
+    get:
+      tags: [pets]
+      parameters:
+      - {name: id, in: path, required: true, schema: {type: string}}
+      responses: {"200": {description: ok}}
+`
+	swagger, err := ReadSwagger(strings.NewReader(spec))
+	if err != nil {
+		t.Fatal(err)
+	}
+	plain, err := marshalSwagger(swagger, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	setFlag(t, groupByTag, true)
+	grouped, err := marshalSwagger(swagger, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var keys []string
+	for _, line := range strings.Split(string(grouped), "\n") {
+		if strings.HasPrefix(line, "  ") && !strings.HasPrefix(line, "   ") && !strings.HasPrefix(line, "  -") {
+			keys = append(keys, line)
+		}
+	}
+	want := []string{"  title: Pets", "  version: \"1\"", "  # owners", "  /owners:", "  # pets", "  /pets:", "  /pets/{id}:", "  # (untagged)", "  /health:"}
+	if !reflect.DeepEqual(keys, want) {
+		t.Errorf("got keys %q, want %q in\n%s", keys, want, grouped)
+	}
+
+	var plainValue, groupedValue interface{}
+	if err := yaml.Unmarshal(plain, &plainValue); err != nil {
+		t.Fatal(err)
+	}
+	if err := yaml.Unmarshal(grouped, &groupedValue); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(plainValue, groupedValue) {
+		t.Errorf("grouping changed the spec:\n%s", grouped)
+	}
+}
+
+func TestCheckDuplicateKeys(t *testing.T) {
+	spec := `openapi: 3.0.3
+info: {title: Pets, version: "1"}
+paths:
+  /pets:
+    get:
+      responses:
+        "200": {description: ok}
+    get:
+      parameters:
+      - {name: limit, in: query, name: size}
+      responses: {}
+`
+	err := checkDuplicateKeys([]byte(spec))
+	if err == nil {
+		t.Fatal("expected an error for the repeated keys")
+	}
+	for _, want := range []string{
+		`line 8, column 5: paths./pets: key "get" repeats the one on line 5`,
+		`line 10, column 34: paths./pets.get.parameters[0]: key "name" repeats the one on line 10`,
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("got %q, want it to contain %q", err, want)
+		}
+	}
+	if err := checkDuplicateKeys([]byte("a: {b: 1}\nc: {b: 2}\n")); err != nil {
+		t.Errorf("keys repeated in different mappings: got %v, want no error", err)
+	}
+}
+
+func TestStreamMatchesInMemory(t *testing.T) {
+	sample := `[
+		{"id": 1, "name": "Rex", "tags": ["a"], "owner": {"id": 7}},
+		{"id": 2, "name": "Tom", "weight": 4.5, "tags": [], "owner": {"id": 8, "email": "tom@example.com"}},
+		{"id": 3, "name": null, "weight": 5, "tags": ["b", "c"], "owner": {"id": 9}, "toys": [{"kind": "ball"}, {"kind": "rope", "colour": "red"}]},
+		{"id": "4", "name": "Kit"}
+	]`
+
+	inferenceWarnings = nil
+	streamed, err := streamArraySchema(strings.NewReader(sample))
+	if err != nil {
+		t.Fatal(err)
+	}
+	streamedWarnings := inferenceWarnings
+
+	inferenceWarnings = nil
+	var data []interface{}
+	if err := decodeJSON([]byte(sample), &data); err != nil {
+		t.Fatal(err)
+	}
+	inMemory, err := inferSchema(data, "", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(streamed, inMemory) {
+		t.Errorf("streamed schema differs from the in-memory one:\n%s\nvs\n%s", mustMarshal(t, streamed), mustMarshal(t, inMemory))
+	}
+	if !reflect.DeepEqual(streamedWarnings, inferenceWarnings) {
+		t.Errorf("streamed warnings %q differ from the in-memory ones %q", streamedWarnings, inferenceWarnings)
+	}
+	if _, ok := inMemory.Items.Properties["toys"].Items.Properties["colour"]; !ok {
+		t.Error("properties of later array elements are missing from the item schema")
+	}
+}
+
+func TestMergeNullElements(t *testing.T) {
+	tests := []struct {
+		sample string
+		want   string
+	}{
+		{`{"ids": [1, null]}`, "integer"},
+		{`{"ids": [null, 1]}`, "integer"},
+		{`{"ids": [1, 2.5, null]}`, "number"},
+		{`{"ids": [null, null]}`, "string"},
+	}
+	for _, test := range tests {
+		inferenceWarnings = nil
+		schema := sampleSchema(t, test.sample)
+		items := schema.Properties["ids"].Items
+		if items.Type != test.want || !items.Nullable {
+			t.Errorf("%s: got items %s, nullable %v, want nullable %s", test.sample, items.Type, items.Nullable, test.want)
+		}
+		if want := []string{"ids[]: value is null, type defaulted to string"}; !reflect.DeepEqual(inferenceWarnings, want) {
+			t.Errorf("%s: got warnings %q, want %q", test.sample, inferenceWarnings, want)
+		}
+
+		var data interface{}
+		if err := decodeJSON([]byte(test.sample), &data); err != nil {
+			t.Fatal(err)
+		}
+		if mismatches := sampleMismatches(&SwaggerTemplate{}, schema, data, "", nil); len(mismatches) > 0 {
+			t.Errorf("%s: the sample doesn't match its schema: %q", test.sample, mismatches)
+		}
+	}
+}
+
+func TestMergeWidensNumbersInPlace(t *testing.T) {
+	a := Schema{Type: "integer", Format: "int64", Description: "Weight", Nullable: true, Example: 4}
+	got := mergeSchemas(a, Schema{Type: "number"}, "weight", nil)
+	want := Schema{Type: "number", Description: "Weight", Nullable: true, Example: 4}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %#v, want %#v", got, want)
+	}
+}
+
+func TestSampleFilesMergeNulls(t *testing.T) {
+	dir := t.TempDir()
+	withNull := filepath.Join(dir, "null.json")
+	withValue := filepath.Join(dir, "value.json")
+	if err := os.WriteFile(withNull, []byte(`{"id": 1, "note": null}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(withValue, []byte(`{"id": 2, "note": {"text": "hi"}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, paths := range [][]string{{withNull, withValue}, {withValue, withNull}} {
+		schema, _, err := sampleFilesSchema(paths)
+		if err != nil {
+			t.Fatal(err)
+		}
+		note := schema.Properties["note"]
+		if note.Type != "object" || !note.Nullable || note.Properties["text"].Type != "string" {
+			t.Errorf("%s then %s: got note\n%s", filepath.Base(paths[0]), filepath.Base(paths[1]), mustMarshal(t, note))
+		}
+		if !reflect.DeepEqual(schema.Required, []string{"id", "note"}) {
+			t.Errorf("%s then %s: got required %q, want id and note", filepath.Base(paths[0]), filepath.Base(paths[1]), schema.Required)
+		}
+	}
+}
+
+func mustMarshal(t *testing.T, value interface{}) string {
+	t.Helper()
+	data, err := yaml.Marshal(value)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestStreamWarnsAboutEmptyArrays(t *testing.T) {
+	inferenceWarnings = nil
+	if _, err := streamArraySchema(strings.NewReader("[]")); err != nil {
+		t.Fatal(err)
+	}
+	streamed := inferenceWarnings
+
+	inferenceWarnings = nil
+	if _, err := inferSchema([]interface{}{}, "", 0); err != nil {
+		t.Fatal(err)
+	}
+	if len(streamed) != 1 || !reflect.DeepEqual(streamed, inferenceWarnings) {
+		t.Errorf("got warnings %q, want the in-memory ones %q", streamed, inferenceWarnings)
+	}
+}
+
+func TestFixNullableSchemas(t *testing.T) {
+	tests := []struct {
+		name    string
+		version string
+		schema  string
+		want    string
+	}{
+		{"3.0 nullable enum", "3.0.3", `{type: string, nullable: true, enum: [a, b]}`, "type: string\nnullable: true\nenum:\n- a\n- b\n- null\n"},
+		{"3.1 nullable enum", "3.1.0", `{type: string, nullable: true, enum: [a, b]}`, "type:\n- string\n- \"null\"\nenum:\n- a\n- b\n- null\n"},
+		{"3.1 type list enum", "3.1.0", `{type: [string, "null"], enum: [a]}`, "type:\n- string\n- \"null\"\nenum:\n- a\n- null\n"},
+		{"3.0 type list", "3.0.3", `{type: [integer, "null"]}`, "type: integer\nnullable: true\n"},
+		{"3.0 enum without nullable", "3.0.3", `{type: string, enum: [a]}`, "type: string\nenum:\n- a\n"},
+	}
+	for _, test := range tests {
+		var schema Schema
+		if err := yaml.Unmarshal([]byte(test.schema), &schema); err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		swagger := &SwaggerTemplate{OpenAPI: test.version, Components: Components{Schemas: map[string]Schema{"A": schema}}}
+		fixNullableSchemas(swagger)
+		if got := mustMarshal(t, swagger.Components.Schemas["A"]); got != test.want {
+			t.Errorf("%s: got\n%s\nwant\n%s", test.name, got, test.want)
+		}
+	}
+}
+
+func TestValidateTypeLists(t *testing.T) {
+	for _, test := range []struct {
+		version string
+		want    int
+	}{
+		{"3.0.3", 1},
+		{"3.1.0", 0},
+	} {
+		var schema Schema
+		if err := yaml.Unmarshal([]byte(`{type: [string, "null"]}`), &schema); err != nil {
+			t.Fatal(err)
+		}
+		swagger := &SwaggerTemplate{OpenAPI: test.version, Components: Components{Schemas: map[string]Schema{"A": schema}}}
+		if got := validateTypeLists(swagger); len(got) != test.want {
+			t.Errorf("%s: got problems %q, want %d", test.version, got, test.want)
+		}
+	}
+}
+
+func TestInferBinary(t *testing.T) {
+	setFlag(t, inferBinary, true)
+	blob := make([]byte, 96)
+	for i := range blob {
+		blob[i] = byte(i * 7)
+	}
+	encoded := base64.StdEncoding.EncodeToString(blob)
+
+	tests := []struct {
+		name   string
+		value  string
+		format string
+	}{
+		{"data", encoded, "byte"},
+		{"data", "The quick brown fox jumps over the lazy dog, then naps in the warm afternoon sun.", ""},
+		{"data", "dGVzdA==", ""},
+		{"data", strings.Repeat("abcd", 20), ""},
+		{"file", "report", "binary"},
+		{"user_file", "report", "binary"},
+		{"avatarBlob", "report", "binary"},
+		{"profile", "report", ""},
+		{"myfile", "report", ""},
+		{"prefile", "report", ""},
+	}
+	for _, test := range tests {
+		prop := sampleSchema(t, `{"`+test.name+`": "`+test.value+`"}`).Properties[test.name]
+		if prop.Type != "string" || prop.Format != test.format {
+			t.Errorf("%s: %.20q: got %s/%s, want string/%s", test.name, test.value, prop.Type, prop.Format, test.format)
+		}
+	}
+}
+
+func TestInferredSchemaAcceptsItsSample(t *testing.T) {
+	sample := `{"id": 1, "note": null, "tags": ["a", null], "owner": {"email": null}}`
+	schema := sampleSchema(t, sample)
+	var data interface{}
+	if err := decodeJSON([]byte(sample), &data); err != nil {
+		t.Fatal(err)
+	}
+	if mismatches := sampleMismatches(&SwaggerTemplate{}, schema, data, "", nil); len(mismatches) > 0 {
+		t.Errorf("the sample doesn't match the schema inferred from it: %q", mismatches)
+	}
+}
+
+func TestURIFormat(t *testing.T) {
+	tests := []struct {
+		value  string
+		format string
+	}{
+		{"https://example.com", "uri"},
+		{"https://example.com/pets?limit=10", "uri"},
+		{"ftp://files.example.com/report.csv", "uri"},
+		{"/pets/123", ""},
+		{"pets/123", ""},
+		{"hello world", ""},
+		{"note:done", ""},
+		{"https://example.com/a b", ""},
+	}
+	for _, test := range tests {
+		prop := sampleSchema(t, `{"link": "`+test.value+`"}`).Properties["link"]
+		if prop.Type != "string" || prop.Format != test.format {
+			t.Errorf("%q: got %s/%s, want string/%s", test.value, prop.Type, prop.Format, test.format)
+		}
+	}
+}
+
+func TestYAMLSampleMatchesJSON(t *testing.T) {
+	yamlSample := `id: 12
+name: Rex
+weight: 4.5
+vaccinated: true
+nickname: null
+born: 2020-01-02T03:04:05Z
+tags: [a, b]
+owner:
+  email: owner@example.com
+  phones:
+    - {kind: home, number: "555-0100"}
+`
+	jsonSample := `{"id": 12, "name": "Rex", "weight": 4.5, "vaccinated": true, "nickname": null,
+		"born": "2020-01-02T03:04:05Z", "tags": ["a", "b"],
+		"owner": {"email": "owner@example.com", "phones": [{"kind": "home", "number": "555-0100"}]}}`
+
+	filename := filepath.Join(t.TempDir(), "sample.yaml")
+	if err := os.WriteFile(filename, []byte(yamlSample), 0644); err != nil {
+		t.Fatal(err)
+	}
+	data, _, err := readYAMLSample(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fromYAML, err := generateSchema(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fromJSON := sampleSchema(t, jsonSample); !reflect.DeepEqual(fromYAML, fromJSON) {
+		t.Errorf("YAML sample schema differs from the JSON one:\n%s\nvs\n%s", mustMarshal(t, fromYAML), mustMarshal(t, fromJSON))
+	}
+}
+
+func TestYAMLSampleRejectsNonStringKeys(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "sample.yaml")
+	if err := os.WriteFile(filename, []byte("codes:\n  200: ok\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := readYAMLSample(filename); err == nil {
+		t.Error("expected an error for the integer key")
+	}
+}
+
+func TestExtractRecursiveComponents(t *testing.T) {
+	tests := []struct {
+		name   string
+		sample string
+		want   map[string]string
+	}{
+		{
+			"binary tree",
+			`{"v": 1, "left": {"v": 2, "left": {"v": 3}, "right": {"v": 4}}, "right": {"v": 5, "left": {"v": 6}, "right": {"v": 7}}}`,
+			map[string]string{"Node": "type: object\nproperties:\n  left:\n    $ref: '#/components/schemas/Node'\n  right:\n    $ref: '#/components/schemas/Node'\n  v:\n    type: integer\n"},
+		},
+		{
+			"children list",
+			`{"name": "a", "children": [{"name": "b", "children": [{"name": "c", "children": []}]}]}`,
+			map[string]string{"Node": "type: object\nproperties:\n  children:\n    type: array\n    items:\n      $ref: '#/components/schemas/Node'\n  name:\n    type: string\n"},
+		},
+		{
+			"recursion below the root",
+			`{"id": 1, "owner": {"id": 2, "team": {"lead": {"id": 3, "team": {"lead": {"id": 4}}}}}}`,
+			map[string]string{
+				"Node":  "type: object\nproperties:\n  id:\n    type: integer\n  owner:\n    $ref: '#/components/schemas/Owner'\n",
+				"Owner": "type: object\nproperties:\n  id:\n    type: integer\n  team:\n    type: object\n    properties:\n      lead:\n        $ref: '#/components/schemas/Owner'\n",
+			},
+		},
+		{
+			"nested recursion with the same base name",
+			`{"id": 1, "owner": {"alt": {"alt": {"q": 1}, "owner": {"q": 1}}, "owner": {"k": 1, "owner": {"k": 2, "owner": {"z": 1}}}}}`,
+			map[string]string{
+				"Node":   "type: object\nproperties:\n  id:\n    type: integer\n  owner:\n    $ref: '#/components/schemas/Owner'\n",
+				"Owner":  "type: object\nproperties:\n  alt:\n    $ref: '#/components/schemas/Owner'\n  owner:\n    $ref: '#/components/schemas/Owner2'\n",
+				"Owner2": "type: object\nproperties:\n  k:\n    type: integer\n  owner:\n    $ref: '#/components/schemas/Owner2'\n",
+			},
+		},
+	}
+	for _, test := range tests {
+		swagger := &SwaggerTemplate{Components: Components{Schemas: map[string]Schema{"Node": sampleSchema(t, test.sample)}}}
+		extractRecursiveComponents(swagger, "Node")
+		got := make(map[string]string)
+		for name, schema := range swagger.Components.Schemas {
+			got[name] = mustMarshal(t, schema)
+		}
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%s: got %q, want %q", test.name, got, test.want)
+		}
+	}
+}
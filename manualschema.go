@@ -0,0 +1,145 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+var manualSchemaTypes = []string{"string", "integer", "number", "boolean", "array", "object"}
+
+// Build an object schema by prompting for each property in turn. Nested
+// objects and array items are prompted for recursively; path names the
+// schema being built so the user knows where they are.
+func buildSchemaManually(reader *bufio.Reader, path string) Schema {
+	schema := Schema{Type: "object", Properties: make(map[string]Schema)}
+
+	for {
+		name := prompt(reader, fmt.Sprintf("Enter property name for %s (blank to finish): ", displayKeyPath(path)))
+		if name == "" {
+			// An object without fixed properties is map-like, so its size
+			// may be bounded instead
+			if len(schema.Properties) == 0 {
+				schema.MinProperties, schema.MaxProperties = promptPropertyBounds(reader, path)
+			}
+			return schema
+		}
+		schema.Properties[name] = promptPropertySchema(reader, joinKeyPath(path, name))
+	}
+}
+
+// Prompt for the type of a single property and whatever that type needs
+func promptPropertySchema(reader *bufio.Reader, path string) Schema {
+	propType := promptSchemaType(reader, path)
+
+	switch propType {
+	case "object":
+		return buildSchemaManually(reader, path)
+	case "array":
+		items := promptPropertySchema(reader, path+"[]")
+		return Schema{Type: "array", Items: &items}
+	case "string":
+		return Schema{Type: "string", Enum: promptEnumValues(reader, path)}
+	default:
+		return Schema{Type: propType}
+	}
+}
+
+// Prompt for bounds on the number of properties of an object, re-prompting
+// until they are valid. Either bound may be nil.
+func promptPropertyBounds(reader *bufio.Reader, path string) (*int, *int) {
+	for {
+		input := prompt(reader, fmt.Sprintf("Enter the allowed number of properties for %s as min-max, e.g. 1-10 or 1- (blank for any): ", displayKeyPath(path)))
+		min, max, err := parsePropertyBounds(input)
+		if err != nil {
+			fmt.Println("Invalid bounds:", err)
+			continue
+		}
+		return min, max
+	}
+}
+
+// Parse property count bounds written as min-max, where either side may be
+// left out, or as a single exact count
+func parsePropertyBounds(input string) (*int, *int, error) {
+	input = strings.TrimSpace(input)
+	if input == "" {
+		return nil, nil, nil
+	}
+
+	low, high, isRange := strings.Cut(input, "-")
+	if !isRange {
+		high = low
+	}
+	var bounds [2]*int
+	for i, text := range []string{low, high} {
+		if text = strings.TrimSpace(text); text == "" {
+			continue
+		}
+		n, err := strconv.Atoi(text)
+		if err != nil || n < 0 {
+			return nil, nil, fmt.Errorf("%q is not a non-negative count", text)
+		}
+		bounds[i] = &n
+	}
+	if bounds[0] != nil && bounds[1] != nil && *bounds[0] > *bounds[1] {
+		return nil, nil, fmt.Errorf("minimum %d is more than maximum %d", *bounds[0], *bounds[1])
+	}
+	return bounds[0], bounds[1], nil
+}
+
+// Prompt until the user enters one of the supported schema types,
+// defaulting to string when left blank
+func promptSchemaType(reader *bufio.Reader, path string) string {
+	label := fmt.Sprintf("Enter type for %s (%s, blank for string): ", path, strings.Join(manualSchemaTypes, "/"))
+	for {
+		propType := strings.ToLower(prompt(reader, label))
+		if propType == "" {
+			return "string"
+		}
+		for _, known := range manualSchemaTypes {
+			if propType == known {
+				return propType
+			}
+		}
+		fmt.Printf("Invalid type %q.\n", propType)
+	}
+}
+
+// Prompt for the allowed values of a string property, re-prompting until
+// the list is valid. Returns nil when the user leaves it blank.
+func promptEnumValues(reader *bufio.Reader, path string) []interface{} {
+	for {
+		input := prompt(reader, fmt.Sprintf("Enter comma-separated enum values for %s (blank for none): ", path))
+		if input == "" {
+			return nil
+		}
+
+		values, err := parseEnumValues(input)
+		if err != nil {
+			fmt.Println("Invalid enum values:", err)
+			continue
+		}
+		return values
+	}
+}
+
+// Split a comma-separated list of enum values, rejecting empty and
+// duplicate entries
+func parseEnumValues(input string) ([]interface{}, error) {
+	var values []interface{}
+	seen := make(map[string]bool)
+	for _, value := range strings.Split(input, ",") {
+		value = strings.TrimSpace(value)
+		if value == "" {
+			return nil, fmt.Errorf("empty value in %q", input)
+		}
+		if seen[value] {
+			return nil, fmt.Errorf("duplicate value %q", value)
+		}
+		seen[value] = true
+		values = append(values, value)
+	}
+	return values, nil
+}
@@ -0,0 +1,219 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"strings"
+)
+
+// Write a Markdown reference for a spec, to outputPath or to stdout when
+// it is empty
+func markdownSwagger(filePath, outputPath string) error {
+	swagger, err := readSwaggerFile(filePath)
+	if err != nil {
+		return err
+	}
+
+	markdown := formatMarkdown(swagger)
+	if outputPath == "" {
+		fmt.Print(markdown)
+		return nil
+	}
+	if err := ioutil.WriteFile(outputPath, []byte(markdown), 0644); err != nil {
+		return err
+	}
+	fmt.Println("Markdown reference written successfully.")
+	return nil
+}
+
+// Format a spec as Markdown: a section per primary tag with the operations
+// under it, untagged operations last, then the component schemas that
+// operations link to
+func formatMarkdown(swagger *SwaggerTemplate) string {
+	var sb strings.Builder
+	title, _ := swagger.Info["title"].(string)
+	if title == "" {
+		title = "API reference"
+	}
+	fmt.Fprintf(&sb, "# %s\n", title)
+	if version, ok := swagger.Info["version"]; ok {
+		fmt.Fprintf(&sb, "\nVersion %v\n", version)
+	}
+	if description, ok := swagger.Info["description"].(string); ok && description != "" {
+		fmt.Fprintf(&sb, "\n%s\n", description)
+	}
+
+	type endpoint struct {
+		path, method string
+	}
+	byTag := make(map[string][]endpoint)
+	var untagged []endpoint
+	for _, path := range sortedKeys(swagger.Paths) {
+		for _, method := range sortedKeys(swagger.Paths[path].Operations) {
+			if tags := swagger.Paths[path].Operations[method].Tags; len(tags) > 0 {
+				byTag[tags[0]] = append(byTag[tags[0]], endpoint{path, method})
+			} else {
+				untagged = append(untagged, endpoint{path, method})
+			}
+		}
+	}
+	sections := sortedKeys(byTag)
+	if len(untagged) > 0 {
+		byTag["Other"] = append(byTag["Other"], untagged...)
+		if !containsString(sections, "Other") {
+			sections = append(sections, "Other")
+		}
+	}
+
+	for _, tag := range sections {
+		fmt.Fprintf(&sb, "\n## %s\n", tag)
+		for _, e := range byTag[tag] {
+			item := swagger.Paths[e.path]
+			operation := item.Operations[e.method]
+			operation.Parameters = effectiveParameters(item, operation)
+			writeMarkdownOperation(&sb, e.path, e.method, operation)
+		}
+	}
+
+	if len(swagger.Components.Schemas) > 0 {
+		sb.WriteString("\n## Schemas\n")
+		for _, name := range sortedKeys(swagger.Components.Schemas) {
+			schema := swagger.Components.Schemas[name]
+			fmt.Fprintf(&sb, "\n### %s\n\n", name)
+			if schema.Deprecated && schema.Sunset != "" {
+				fmt.Fprintf(&sb, "**Deprecated, sunset %s.**\n\n", schema.Sunset)
+			} else if schema.Deprecated {
+				sb.WriteString("**Deprecated.**\n\n")
+			}
+			writeMarkdownSchema(&sb, schema, "")
+		}
+	}
+	return sb.String()
+}
+
+// Write one operation: its heading, description, parameters table,
+// request body and responses table
+func writeMarkdownOperation(sb *strings.Builder, path, method string, operation Operation) {
+	fmt.Fprintf(sb, "\n### `%s %s`\n", strings.ToUpper(method), path)
+	if operation.Summary != "" {
+		fmt.Fprintf(sb, "\n%s\n", operation.Summary)
+	}
+	if operation.Description != "" {
+		fmt.Fprintf(sb, "\n%s\n", operation.Description)
+	}
+	if operation.Deprecated {
+		sb.WriteString("\n**Deprecated.**\n")
+	}
+
+	if len(operation.Parameters) > 0 {
+		sb.WriteString("\n**Parameters**\n\n| Name | In | Type | Required | Description |\n| --- | --- | --- | --- | --- |\n")
+		for _, param := range operation.Parameters {
+			paramType := "any"
+			if param.Schema != nil {
+				paramType = markdownType(*param.Schema)
+			}
+			required := "no"
+			if param.Required {
+				required = "yes"
+			}
+			fmt.Fprintf(sb, "| `%s` | %s | %s | %s | %s |\n", param.Name, param.In, markdownCell(paramType), required, markdownCell(param.Description))
+		}
+	}
+
+	if body := operation.RequestBody; body != nil {
+		sb.WriteString("\n**Request body**")
+		if body.Required {
+			sb.WriteString(" (required)")
+		}
+		sb.WriteString("\n")
+		if body.Description != "" {
+			fmt.Fprintf(sb, "\n%s\n", body.Description)
+		}
+		writeMarkdownContent(sb, body.Content)
+	}
+
+	if len(operation.Responses) > 0 {
+		sb.WriteString("\n**Responses**\n\n| Status | Description | Content |\n| --- | --- | --- |\n")
+		for _, status := range sortedKeys(operation.Responses) {
+			response := operation.Responses[status]
+			mediaTypes := sortedKeys(response.Content)
+			for i, mediaType := range mediaTypes {
+				mediaTypes[i] = "`" + mediaType + "`"
+			}
+			fmt.Fprintf(sb, "| %s | %s | %s |\n", status, markdownCell(response.Description), strings.Join(mediaTypes, ", "))
+		}
+		for _, status := range sortedKeys(operation.Responses) {
+			if content := operation.Responses[status].Content; len(content) > 0 {
+				fmt.Fprintf(sb, "\n%s response:\n", status)
+				writeMarkdownContent(sb, content)
+			}
+		}
+	}
+}
+
+// Write the schema of each media type in a content map
+func writeMarkdownContent(sb *strings.Builder, content map[string]MediaType) {
+	for _, mediaType := range sortedKeys(content) {
+		fmt.Fprintf(sb, "\n`%s`\n\n", mediaType)
+		writeMarkdownSchema(sb, content[mediaType].Schema, "")
+	}
+}
+
+// Write a schema as a bullet list with a line per property, nesting the
+// properties of objects and of array items under them
+func writeMarkdownSchema(sb *strings.Builder, schema Schema, indent string) {
+	members := schema
+	if schema.Type == "array" && schema.Items != nil {
+		members = *schema.Items
+	}
+	if len(members.Properties) == 0 {
+		fmt.Fprintf(sb, "%s- %s\n", indent, markdownType(schema))
+		return
+	}
+	if indent == "" && schema.Type == "array" {
+		fmt.Fprintf(sb, "- %s\n", markdownType(schema))
+		indent = "  "
+	}
+	writeMarkdownProperties(sb, members, indent)
+}
+
+// Write the properties of an object schema as bullets
+func writeMarkdownProperties(sb *strings.Builder, schema Schema, indent string) {
+	for _, name := range sortedKeys(schema.Properties) {
+		prop := schema.Properties[name]
+		line := fmt.Sprintf("%s- `%s`: %s%s", indent, name, markdownType(prop), deprecationNote(prop))
+		if containsString(schema.Required, name) {
+			line += ", required"
+		}
+		if prop.Description != "" {
+			line += " — " + prop.Description
+		}
+		sb.WriteString(line + "\n")
+
+		members := prop
+		if prop.Type == "array" && prop.Items != nil {
+			members = *prop.Items
+		}
+		writeMarkdownProperties(sb, members, indent+"  ")
+	}
+}
+
+// Describe a schema's type for Markdown, linking component refs to their
+// section under Schemas. Deprecation is left to the caller, so that it's
+// noted for refs and arrays too.
+func markdownType(schema Schema) string {
+	if schema.Ref != "" {
+		name := strings.TrimPrefix(schema.Ref, schemaRefPrefix)
+		return fmt.Sprintf("[%s](#%s)", name, strings.ToLower(name))
+	}
+	if schema.Type == "array" && schema.Items != nil {
+		return "array of " + markdownType(*schema.Items)
+	}
+	schema.Deprecated = false
+	return schemaSummary(schema)
+}
+
+// Make text safe for a Markdown table cell
+func markdownCell(text string) string {
+	return strings.NewReplacer("|", `\|`, "\n", " ").Replace(text)
+}
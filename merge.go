@@ -0,0 +1,119 @@
+package main
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// Merge the paths, components and servers of another spec into a base
+// spec. The base keeps its info block. Operations or component schemas
+// defined differently in both files are conflicts; when there are any,
+// nothing is written.
+func mergeSwagger(filePath, otherPath string) error {
+	unlock, err := lockFile(filePath)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	base, err := readSwaggerFile(filePath)
+	if err != nil {
+		return err
+	}
+	other, err := readSwaggerFile(otherPath)
+	if err != nil {
+		return err
+	}
+
+	if conflicts := mergeSpecs(base, other); len(conflicts) > 0 {
+		return fmt.Errorf("%d conflicts found:\n  %s", len(conflicts), strings.Join(conflicts, "\n  "))
+	}
+	return writeSwaggerFile(outputFile(filePath), base)
+}
+
+// Merge other into base in place, returning a description of each conflict
+func mergeSpecs(base, other *SwaggerTemplate) []string {
+	var conflicts []string
+
+	for _, path := range sortedKeys(other.Paths) {
+		ensurePathItem(base, path)
+		item := base.Paths[path]
+		var paramConflicts []string
+		item.Parameters, paramConflicts = mergePathParameters(item.Parameters, other.Paths[path].Parameters, path)
+		conflicts = append(conflicts, paramConflicts...)
+		base.Paths[path] = item
+
+		for _, method := range sortedKeys(other.Paths[path].Operations) {
+			operation := other.Paths[path].Operations[method]
+			existing, exists := base.Paths[path].Operations[method]
+			if exists && !reflect.DeepEqual(existing, operation) {
+				conflicts = append(conflicts, fmt.Sprintf("%s %s is defined differently in both files", strings.ToUpper(method), path))
+				continue
+			}
+			base.Paths[path].Operations[method] = operation
+		}
+	}
+
+	for _, name := range sortedKeys(other.Components.Schemas) {
+		schema := other.Components.Schemas[name]
+		existing, exists := base.Components.Schemas[name]
+		if exists && !reflect.DeepEqual(existing, schema) {
+			conflicts = append(conflicts, fmt.Sprintf("component schema %s is defined differently in both files", name))
+			continue
+		}
+		if base.Components.Schemas == nil {
+			base.Components.Schemas = make(map[string]Schema)
+		}
+		base.Components.Schemas[name] = schema
+	}
+	conflicts = append(conflicts, mergeComponentSections(&base.Components, other.Components)...)
+
+	for _, server := range other.Servers {
+		known := false
+		for _, existing := range base.Servers {
+			known = known || existing.URL == server.URL
+		}
+		if !known {
+			base.Servers = append(base.Servers, server)
+		}
+	}
+	return conflicts
+}
+
+// Merge the unmodelled components sections of other, such as
+// securitySchemes, entry by entry. Entries defined differently in both
+// files are conflicts, as are sections that aren't maps in either file.
+func mergeComponentSections(base *Components, other Components) []string {
+	var conflicts []string
+	for _, section := range sortedKeys(other.Extensions) {
+		value := other.Extensions[section]
+		existing, exists := base.Extensions[section]
+		if !exists {
+			if base.Extensions == nil {
+				base.Extensions = make(map[string]interface{})
+			}
+			base.Extensions[section] = value
+			continue
+		}
+
+		entries, ok := value.(map[interface{}]interface{})
+		existingEntries, existingOK := existing.(map[interface{}]interface{})
+		if !ok || !existingOK {
+			if !reflect.DeepEqual(existing, value) {
+				conflicts = append(conflicts, fmt.Sprintf("components.%s is defined differently in both files", section))
+			}
+			continue
+		}
+		for key, entry := range entries {
+			if existingEntry, exists := existingEntries[key]; exists && !reflect.DeepEqual(existingEntry, entry) {
+				conflicts = append(conflicts, fmt.Sprintf("components.%s.%v is defined differently in both files", section, key))
+				continue
+			}
+			existingEntries[key] = entry
+		}
+	}
+	sort.Strings(conflicts)
+	return conflicts
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"math/rand"
+	"strings"
+)
+
+var mockSeed = flag.Int64("seed", 1, "seed for the choices mock makes, such as which enum value to use and how long arrays are; the same seed and schema always give the same output, but any change to the schema can change it")
+
+// Placeholder values for strings of well-known formats
+var mockStringFormats = map[string]string{
+	"date":      "2024-01-01",
+	"date-time": "2024-01-01T00:00:00Z",
+	"uuid":      "00000000-0000-0000-0000-000000000000",
+	"email":     "user@example.com",
+	"uri":       "https://example.com",
+	"byte":      "c3RyaW5n",
+}
+
+// Print a JSON instance of an operation's response schema
+func mockResponse(filePath, path, method, status string) error {
+	swagger, err := readSwaggerFile(filePath)
+	if err != nil {
+		return err
+	}
+
+	operation, ok := swagger.Paths[path].Operations[method]
+	if !ok {
+		return fmt.Errorf("operation %s %s not found", strings.ToUpper(method), path)
+	}
+	response, ok := operation.Responses[status]
+	if !ok {
+		return fmt.Errorf("operation %s %s has no %s response", strings.ToUpper(method), path, status)
+	}
+	if len(response.Content) == 0 {
+		return fmt.Errorf("the %s response has no content", status)
+	}
+
+	// Prefer JSON when the response has several media types
+	mediaType := sortedKeys(response.Content)[0]
+	if _, ok := response.Content["application/json"]; ok {
+		mediaType = "application/json"
+	}
+
+	rng := rand.New(rand.NewSource(*mockSeed))
+	data, err := json.MarshalIndent(mockValue(swagger, response.Content[mediaType].Schema, rng, nil), "", "  ")
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(data))
+	return nil
+}
+
+// Build a value conforming to a schema, using its const, example or default
+// when it has one, an enum value picked with rng, or a placeholder for its
+// type. Arrays get one to three elements, also picked with rng. Properties
+// are visited in sorted order so that a seed always gives the same value.
+// refs holds the components being expanded, so that recursive schemas stop
+// instead of looping.
+func mockValue(swagger *SwaggerTemplate, schema Schema, rng *rand.Rand, refs []string) interface{} {
+	if schema.Ref != "" {
+		name := strings.TrimPrefix(schema.Ref, schemaRefPrefix)
+		target, ok := swagger.Components.Schemas[name]
+		if !ok || containsString(refs, name) {
+			return nil
+		}
+		return mockValue(swagger, target, rng, append(refs, name))
+	}
+
+	for _, value := range []interface{}{schema.Const, schema.Example, schema.Default} {
+		if value != nil {
+			if normalized, err := normalizeYAMLValue(value); err == nil {
+				return normalized
+			}
+		}
+	}
+	if len(schema.Enum) > 0 {
+		if normalized, err := normalizeYAMLValue(schema.Enum[rng.Intn(len(schema.Enum))]); err == nil {
+			return normalized
+		}
+	}
+
+	switch schema.Type {
+	case "string":
+		if value, ok := mockStringFormats[schema.Format]; ok {
+			return value
+		}
+		return "string"
+	case "integer":
+		return 0
+	case "number":
+		return 0.0
+	case "boolean":
+		return true
+	case "array":
+		if schema.Items == nil {
+			return []interface{}{}
+		}
+		items := make([]interface{}, 1+rng.Intn(3))
+		for i := range items {
+			items[i] = mockValue(swagger, *schema.Items, rng, refs)
+		}
+		return items
+	case "object":
+		object := make(map[string]interface{}, len(schema.Properties))
+		for _, name := range sortedKeys(schema.Properties) {
+			object[name] = mockValue(swagger, schema.Properties[name], rng, refs)
+		}
+		return object
+	}
+	if len(schema.Properties) > 0 {
+		return mockValue(swagger, Schema{Type: "object", Properties: schema.Properties}, rng, refs)
+	}
+	return nil
+}
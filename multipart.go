@@ -0,0 +1,96 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"strings"
+	"unicode"
+)
+
+var (
+	multipart  = flag.Bool("multipart", false, "have update document the sample as a multipart/form-data request body instead of a response")
+	fileFields = flag.String("file-fields", "", "comma-separated form fields to document as binary file uploads with -multipart, in addition to fields named like files")
+)
+
+// Field names that are taken to be file uploads in a multipart body
+var fileFieldNames = []string{"file", "upload", "attachment", "image", "avatar", "photo", "document"}
+
+// Build a multipart/form-data request body from a schema inferred from a
+// sample of the form fields. File fields become binary strings.
+func multipartRequestBody(schema Schema) RequestBody {
+	flagged := make(map[string]bool)
+	for _, name := range strings.Split(*fileFields, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			flagged[name] = true
+		}
+	}
+
+	for name := range schema.Properties {
+		if flagged[name] || isFileFieldName(name) {
+			schema.Properties[name] = Schema{Type: "string", Format: "binary"}
+		}
+	}
+
+	return RequestBody{
+		Content: map[string]MediaType{
+			"multipart/form-data": {Schema: schema},
+		},
+	}
+}
+
+// Report whether a form field name looks like it holds an uploaded file
+func isFileFieldName(name string) bool {
+	return endsWithWord(name, fileFieldNames)
+}
+
+// Report whether a name is one of words, ignoring case, or ends in one as a
+// separate word: after a '_', '-' or '.', or capitalized after a lowercase
+// letter or digit. So user_file and userFile end in file but profile
+// doesn't.
+func endsWithWord(name string, words []string) bool {
+	for _, word := range words {
+		start := len(name) - len(word)
+		if start < 0 || !strings.EqualFold(name[start:], word) {
+			continue
+		}
+		if start == 0 {
+			return true
+		}
+		prev, first := rune(name[start-1]), rune(name[start])
+		if strings.ContainsRune("_-.", prev) || (unicode.IsUpper(first) && (unicode.IsLower(prev) || unicode.IsDigit(prev))) {
+			return true
+		}
+	}
+	return false
+}
+
+// Ask whether a request body is required. Pressing enter takes the usual
+// answer for the method: required for POST and PUT, optional otherwise.
+func promptBodyRequired(reader *bufio.Reader, method string) bool {
+	required := method == "post" || method == "put"
+	defaultAnswer := "n"
+	if required {
+		defaultAnswer = "y"
+	}
+	switch strings.ToLower(prompt(reader, "Is the request body required? (y/n, blank for "+defaultAnswer+"): ")) {
+	case "y":
+		return true
+	case "n":
+		return false
+	}
+	return required
+}
+
+// Set the request body on the operation at path and method, creating the
+// operation with a default response if needed. Reports whether it was created.
+func setOperationRequestBody(swagger *SwaggerTemplate, path, method string, body RequestBody) bool {
+	operation, created := ensureOperation(swagger, path, method)
+	if created {
+		operation.Responses = map[string]Response{
+			"200": {Description: "Successful response"},
+		}
+	}
+	operation.RequestBody = &body
+	swagger.Paths[path].Operations[method] = operation
+	return created
+}
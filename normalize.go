@@ -0,0 +1,76 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Clean up the response status keys of every operation: surrounding space
+// is trimmed, default is lowercased and ranges like 2xx are uppercased.
+// Keys that still aren't a status code, a range or default are reported
+// and left as they are, as are keys whose cleaned form is already taken.
+func normalizeResponses(filePath string) error {
+	unlock, err := lockFile(filePath)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	swagger, err := readSwaggerFile(filePath)
+	if err != nil {
+		return err
+	}
+
+	var fixes, problems []string
+	for _, path := range sortedKeys(swagger.Paths) {
+		for _, method := range sortedKeys(swagger.Paths[path].Operations) {
+			operation := swagger.Paths[path].Operations[method]
+			location := fmt.Sprintf("paths.%s.%s.responses", path, method)
+			for _, status := range sortedKeys(operation.Responses) {
+				normalized := normalizeStatusKey(status)
+				switch {
+				case !statusCodePattern.MatchString(normalized):
+					problems = append(problems, fmt.Sprintf("%s: %q is not a status code, range or default", location, status))
+				case normalized == status:
+				default:
+					if _, taken := operation.Responses[normalized]; taken {
+						problems = append(problems, fmt.Sprintf("%s: %q can't be renamed to %q, which already exists", location, status, normalized))
+						continue
+					}
+					operation.Responses[normalized] = operation.Responses[status]
+					delete(operation.Responses, status)
+					fixes = append(fixes, fmt.Sprintf("%s: renamed %q to %q", location, status, normalized))
+				}
+			}
+			swagger.Paths[path].Operations[method] = operation
+		}
+	}
+
+	for _, fix := range fixes {
+		fmt.Println("fixed:", fix)
+	}
+	for _, problem := range problems {
+		fmt.Println("  " + problem)
+	}
+	if len(fixes) > 0 {
+		if err := writeSwaggerFile(outputFile(filePath), swagger); err != nil {
+			return err
+		}
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("%d response status keys need fixing by hand", len(problems))
+	}
+	if len(fixes) == 0 {
+		fmt.Println("All response status keys are already normalized.")
+	}
+	return nil
+}
+
+// The cleaned form of a response status key
+func normalizeStatusKey(status string) string {
+	status = strings.TrimSpace(status)
+	if strings.EqualFold(status, "default") {
+		return "default"
+	}
+	return strings.ToUpper(status)
+}
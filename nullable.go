@@ -0,0 +1,50 @@
+package main
+
+import "strings"
+
+// Write nullability the way the spec's OpenAPI version expects. 3.0 has no
+// type lists, so a [type, "null"] type becomes nullable: true, and 3.1 has
+// no nullable, so a nullable type becomes [type, "null"]. Nullable schemas
+// with an enum must also accept null, which validators otherwise reject as
+// not one of the allowed values, so null is added to the enum.
+func fixNullableSchemas(swagger *SwaggerTemplate) {
+	is30 := strings.HasPrefix(swagger.OpenAPI, "3.0")
+	walkSpecSchemas(swagger, func(schema *Schema, location string) {
+		switch {
+		case is30 && schema.nullType:
+			schema.nullType = false
+			schema.Nullable = true
+		case !is30 && schema.Nullable && schema.Type != "":
+			schema.Nullable = false
+			schema.nullType = true
+		}
+		if (schema.Nullable || schema.nullType) && len(schema.Enum) > 0 && !enumAllowsNull(schema.Enum) {
+			schema.Enum = append(schema.Enum, nil)
+		}
+	})
+}
+
+// Report schemas with a [type, "null"] type list in a 3.0 spec, where only
+// nullable is allowed
+func validateTypeLists(swagger *SwaggerTemplate) []string {
+	if !strings.HasPrefix(swagger.OpenAPI, "3.0") {
+		return nil
+	}
+	var problems []string
+	walkSpecSchemas(swagger, func(schema *Schema, location string) {
+		if schema.nullType {
+			problems = append(problems, location+`: type lists such as [type, "null"] are only allowed in OpenAPI 3.1, use nullable: true`)
+		}
+	})
+	return problems
+}
+
+// Report whether an enum lists null among its values
+func enumAllowsNull(values []interface{}) bool {
+	for _, value := range values {
+		if value == nil {
+			return true
+		}
+	}
+	return false
+}
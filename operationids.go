@@ -0,0 +1,114 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// How the operationIds of two specs differ, for operations matched by path
+// and method. Renamed and removed IDs break clients generated from the
+// old spec.
+type OperationIDReport struct {
+	Renamed []OperationIDChange `json:"renamed"`
+	Added   []OperationIDChange `json:"added"`
+	Removed []OperationIDChange `json:"removed"`
+}
+
+// The operationId of one operation before and after. OldID is empty for an
+// added ID and NewID for a removed one.
+type OperationIDChange struct {
+	Path   string `json:"path"`
+	Method string `json:"method"`
+	OldID  string `json:"oldId,omitempty"`
+	NewID  string `json:"newId,omitempty"`
+}
+
+// Compare the operationIds of two specs. An operation that only exists in
+// one of them counts as its ID being added or removed.
+func CompareOperationIDs(a, b *SwaggerTemplate) OperationIDReport {
+	report := OperationIDReport{
+		Renamed: []OperationIDChange{},
+		Added:   []OperationIDChange{},
+		Removed: []OperationIDChange{},
+	}
+
+	for _, path := range unionKeys(a.Paths, b.Paths) {
+		for _, method := range unionKeys(a.Paths[path].Operations, b.Paths[path].Operations) {
+			change := OperationIDChange{
+				Path:   path,
+				Method: method,
+				OldID:  a.Paths[path].Operations[method].OperationID,
+				NewID:  b.Paths[path].Operations[method].OperationID,
+			}
+			switch {
+			case change.OldID == change.NewID:
+			case change.OldID == "":
+				report.Added = append(report.Added, change)
+			case change.NewID == "":
+				report.Removed = append(report.Removed, change)
+			default:
+				report.Renamed = append(report.Renamed, change)
+			}
+		}
+	}
+	return report
+}
+
+// Print how operationIds changed between two Swagger YAML files
+func operationIDReport(oldPath, newPath string) error {
+	oldSwagger, err := readSwaggerFile(oldPath)
+	if err != nil {
+		return err
+	}
+	newSwagger, err := readSwaggerFile(newPath)
+	if err != nil {
+		return err
+	}
+
+	report := CompareOperationIDs(oldSwagger, newSwagger)
+	if *diffJSON {
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	fmt.Print(formatOperationIDReport(report))
+	return nil
+}
+
+// Format an operationId report as a human-readable summary
+func formatOperationIDReport(report OperationIDReport) string {
+	var sb strings.Builder
+	if len(report.Renamed)+len(report.Added)+len(report.Removed) == 0 {
+		sb.WriteString("No operationId changes found.\n")
+		return sb.String()
+	}
+
+	writeSection := func(heading string, changes []OperationIDChange, format func(OperationIDChange) string) {
+		if len(changes) == 0 {
+			return
+		}
+		fmt.Fprintf(&sb, "%s (%d):\n", heading, len(changes))
+		for _, change := range changes {
+			fmt.Fprintf(&sb, "  %s %s: %s\n", strings.ToUpper(change.Method), change.Path, format(change))
+		}
+	}
+	writeSection("Renamed", report.Renamed, func(change OperationIDChange) string {
+		return change.OldID + " -> " + change.NewID
+	})
+	writeSection("Added", report.Added, func(change OperationIDChange) string {
+		return change.NewID
+	})
+	writeSection("Removed", report.Removed, func(change OperationIDChange) string {
+		return change.OldID
+	})
+
+	if breaking := len(report.Renamed) + len(report.Removed); breaking > 0 {
+		fmt.Fprintf(&sb, "\n%d changes break clients generated from the old spec.\n", breaking)
+	}
+	return sb.String()
+}
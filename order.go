@@ -0,0 +1,252 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+var sortProperties = flag.Bool("sort-properties", true, "write schema properties in sorted key order; set to false to keep the key order of the JSON sample or existing file instead")
+
+// Record the properties order of schemas read from a spec, so that it can
+// be kept when writing with -sort-properties=false
+func (s *Schema) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	if err := unmarshalTypeList(unmarshal, s); err != nil {
+		return err
+	}
+
+	var order struct {
+		Properties yaml.MapSlice `yaml:"properties"`
+	}
+	if err := unmarshal(&order); err != nil {
+		return err
+	}
+	s.propertyOrder = nil
+	for _, item := range order.Properties {
+		if name, ok := item.Key.(string); ok {
+			s.propertyOrder = append(s.propertyOrder, name)
+		}
+	}
+	return nil
+}
+
+// Decode a schema whose type may be a 3.1 list such as [string, "null"].
+// The list is decoded as the one type it names besides null; lists naming
+// several other types aren't supported.
+func unmarshalTypeList(unmarshal func(interface{}) error, s *Schema) error {
+	type plainSchema Schema
+	var typed struct {
+		Type interface{} `yaml:"type"`
+	}
+	if err := unmarshal(&typed); err != nil {
+		return err
+	}
+	list, ok := typed.Type.([]interface{})
+	if !ok {
+		return unmarshal((*plainSchema)(s))
+	}
+
+	// Decode everything else with the list taken out
+	var fields yaml.MapSlice
+	if err := unmarshal(&fields); err != nil {
+		return err
+	}
+	for i := range fields {
+		if fields[i].Key == "type" {
+			fields = append(fields[:i], fields[i+1:]...)
+			break
+		}
+	}
+	data, err := yaml.Marshal(fields)
+	if err != nil {
+		return err
+	}
+	if err := yaml.Unmarshal(data, (*plainSchema)(s)); err != nil {
+		return err
+	}
+
+	var types []string
+	for _, item := range list {
+		name, _ := item.(string)
+		if name == "null" {
+			s.nullType = true
+		} else {
+			types = append(types, name)
+		}
+	}
+	if len(types) > 1 {
+		return fmt.Errorf("type lists with more than one type besides null are not supported: %v", list)
+	}
+	if len(types) == 1 {
+		s.Type = types[0]
+	}
+	return nil
+}
+
+// Write the properties of a schema in their recorded order when
+// -sort-properties is off. Properties without a recorded position follow
+// in sorted order. Otherwise the schema is written as usual, with yaml.v2
+// sorting the properties map. A type that also allows null is written as a
+// [type, "null"] list.
+func (s Schema) MarshalYAML() (interface{}, error) {
+	type plainSchema Schema
+	ordered := !*sortProperties && len(s.propertyOrder) > 0 && len(s.Properties) > 0
+	if !ordered && !s.nullType {
+		return plainSchema(s), nil
+	}
+
+	fields := schemaFields(s)
+	var properties yaml.MapSlice
+	placed := make(map[string]bool)
+	for _, name := range s.propertyOrder {
+		if prop, ok := s.Properties[name]; ok && !placed[name] {
+			properties = append(properties, yaml.MapItem{Key: name, Value: prop})
+			placed[name] = true
+		}
+	}
+	rest := make([]string, 0, len(s.Properties))
+	for name := range s.Properties {
+		if !placed[name] {
+			rest = append(rest, name)
+		}
+	}
+	sort.Strings(rest)
+	for _, name := range rest {
+		properties = append(properties, yaml.MapItem{Key: name, Value: s.Properties[name]})
+	}
+
+	for i := range fields {
+		switch {
+		case fields[i].Key == "properties" && ordered:
+			fields[i].Value = properties
+		case fields[i].Key == "type" && s.nullType:
+			fields[i].Value = []string{s.Type, "null"}
+		}
+	}
+	if s.nullType && s.Type == "" {
+		fields = append(fields, yaml.MapItem{Key: "type", Value: "null"})
+	}
+	return fields, nil
+}
+
+// The fields of a schema, in the order and under the keys yaml.v2 writes
+// them with, leaving out empty omitempty fields. Inline keys follow the
+// fields in sorted order. Values are left for yaml.v2
+// to marshal, so nested schemas are only marshalled once.
+func schemaFields(s Schema) yaml.MapSlice {
+	value := reflect.ValueOf(s)
+	fields := make(yaml.MapSlice, 0, value.NumField())
+	for i := 0; i < value.NumField(); i++ {
+		field := value.Type().Field(i)
+		tag := field.Tag.Get("yaml")
+		if field.PkgPath != "" || tag == "-" {
+			continue
+		}
+		name, options, _ := strings.Cut(tag, ",")
+		if options == "inline" {
+			extensions := value.Field(i).Interface().(map[string]interface{})
+			for _, key := range sortedKeys(extensions) {
+				fields = append(fields, yaml.MapItem{Key: key, Value: extensions[key]})
+			}
+			continue
+		}
+		if name == "" {
+			name = strings.ToLower(field.Name)
+		}
+		if options == "omitempty" && isEmptyYAMLValue(value.Field(i)) {
+			continue
+		}
+		fields = append(fields, yaml.MapItem{Key: name, Value: value.Field(i).Interface()})
+	}
+	return fields
+}
+
+// Report whether yaml.v2 leaves out a field value tagged omitempty
+func isEmptyYAMLValue(value reflect.Value) bool {
+	switch value.Kind() {
+	case reflect.Slice, reflect.Map, reflect.String:
+		return value.Len() == 0
+	case reflect.Ptr, reflect.Interface:
+		return value.IsNil()
+	}
+	return value.IsZero()
+}
+
+// Find the key order of every object in a JSON document, keyed by the same
+// dotted key paths inferSchema uses. For arrays only the first element is
+// recorded; properties that only later elements have are written after
+// its properties, in sorted order. Returns nil if the document can't be
+// parsed.
+func jsonKeyOrder(data []byte) map[string][]string {
+	order := make(map[string][]string)
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	if err := recordKeyOrder(decoder, "", true, order); err != nil {
+		return nil
+	}
+	return order
+}
+
+// Consume one JSON value from the decoder, recording the key order of the
+// objects in it when record is set
+func recordKeyOrder(decoder *json.Decoder, path string, record bool, order map[string][]string) error {
+	token, err := decoder.Token()
+	if err != nil {
+		return err
+	}
+	delim, ok := token.(json.Delim)
+	if !ok {
+		return nil
+	}
+
+	switch delim {
+	case '{':
+		var keys []string
+		for decoder.More() {
+			keyToken, err := decoder.Token()
+			if err != nil {
+				return err
+			}
+			key, _ := keyToken.(string)
+			keys = append(keys, key)
+			if err := recordKeyOrder(decoder, joinKeyPath(path, key), record, order); err != nil {
+				return err
+			}
+		}
+		if record {
+			order[path] = keys
+		}
+	case '[':
+		first := true
+		for decoder.More() {
+			if err := recordKeyOrder(decoder, path+"[]", record && first, order); err != nil {
+				return err
+			}
+			first = false
+		}
+	}
+
+	// Consume the closing delimiter
+	_, err = decoder.Token()
+	return err
+}
+
+// Set the recorded property order on a generated schema and everything
+// nested in it
+func applyPropertyOrder(schema *Schema, order map[string][]string, path string) {
+	if keys, ok := order[path]; ok {
+		schema.propertyOrder = keys
+	}
+	for name, prop := range schema.Properties {
+		applyPropertyOrder(&prop, order, joinKeyPath(path, name))
+		schema.Properties[name] = prop
+	}
+	if schema.Items != nil {
+		applyPropertyOrder(schema.Items, order, path+"[]")
+	}
+}
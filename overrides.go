@@ -0,0 +1,100 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+var typeOverrides = flag.String("type-overrides", "", "YAML file mapping dotted property paths of the sample to the type and format update should use for them, e.g. owner.birthdate: {type: string, format: date}; use [] for array items, as in tags[].id")
+
+// An explicit type for one property, taking precedence over inference
+type typeOverride struct {
+	Type   string `yaml:"type"`
+	Format string `yaml:"format"`
+}
+
+// Read the -type-overrides file, if any
+func loadTypeOverrides() (map[string]typeOverride, error) {
+	if *typeOverrides == "" {
+		return nil, nil
+	}
+	data, err := ioutil.ReadFile(*typeOverrides)
+	if err != nil {
+		return nil, err
+	}
+	var overrides map[string]typeOverride
+	if err := yaml.UnmarshalStrict(data, &overrides); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", *typeOverrides, err)
+	}
+	for path, override := range overrides {
+		if !containsString(schemaTypes, override.Type) {
+			return nil, fmt.Errorf("%s: %s: invalid type %q", *typeOverrides, path, override.Type)
+		}
+	}
+	return overrides, nil
+}
+
+// Apply type overrides to a generated schema, warning about entries that
+// don't match any property. Overriding the type replaces whatever was
+// inferred for the property, including its properties or items.
+func applyTypeOverrides(schema *Schema, overrides map[string]typeOverride) {
+	for _, path := range sortedKeys(overrides) {
+		override := overrides[path]
+		matched := editSchemaAtKeyPath(schema, path, func(target *Schema) {
+			if target.Type != override.Type {
+				*target = Schema{Title: target.Title, Example: target.Example}
+				if override.Type == "array" {
+					target.Items = &Schema{Type: "string"}
+				}
+			}
+			target.Type = override.Type
+			target.Format = override.Format
+		})
+		if !matched {
+			fmt.Fprintf(os.Stderr, "Warning: type override %s doesn't match any property\n", path)
+		}
+	}
+}
+
+// Apply edit to the schema at a dotted key path as built by joinKeyPath,
+// where a [] suffix steps into array items and a blank path is the schema
+// itself. Reports false when there's no such property.
+func editSchemaAtKeyPath(schema *Schema, path string, edit func(*Schema)) bool {
+	if path == "" {
+		edit(schema)
+		return true
+	}
+	return editAtKeys(schema, strings.Split(path, "."), edit)
+}
+
+func editAtKeys(schema *Schema, keys []string, edit func(*Schema)) bool {
+	if len(keys) == 0 {
+		edit(schema)
+		return true
+	}
+
+	key := keys[0]
+	if key == "[]" {
+		if schema.Items == nil {
+			return false
+		}
+		return editAtKeys(schema.Items, keys[1:], edit)
+	}
+	if strings.HasSuffix(key, "[]") {
+		keys = append([]string{strings.TrimSuffix(key, "[]"), "[]"}, keys[1:]...)
+		return editAtKeys(schema, keys, edit)
+	}
+
+	prop, ok := schema.Properties[key]
+	if !ok {
+		return false
+	}
+	matched := editAtKeys(&prop, keys[1:], edit)
+	schema.Properties[key] = prop
+	return matched
+}
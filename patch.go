@@ -0,0 +1,149 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+var patchDryRun = flag.Bool("dry-run", false, "have patch validate the patched spec and show the old and new value at the pointer without writing the file")
+
+// Set the value at a JSON pointer in a spec, e.g. /paths/~1pets/get/summary.
+// The value is parsed as YAML, so JSON works too and a bare word is a
+// string. Object members are added or replaced, array elements replaced,
+// and a final - appends to an array. With -dry-run the patched spec is
+// validated and the old and new values are shown, but nothing is written.
+func patchSwagger(filePath, pointer, rawValue string) error {
+	unlock, err := lockFile(filePath)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	swagger, err := readSwaggerFile(filePath)
+	if err != nil {
+		return err
+	}
+	var value interface{}
+	if err := yaml.Unmarshal([]byte(rawValue), &value); err != nil {
+		return fmt.Errorf("parsing the value: %w", err)
+	}
+
+	data, err := yaml.Marshal(swagger)
+	if err != nil {
+		return err
+	}
+	var document interface{}
+	if err := yaml.Unmarshal(data, &document); err != nil {
+		return err
+	}
+	document, previous, err := setPointerValue(document, pointerTokens(pointer), value)
+	if err != nil {
+		return fmt.Errorf("%s: %w", pointer, err)
+	}
+
+	if data, err = yaml.Marshal(document); err != nil {
+		return err
+	}
+	patched, err := ReadSwagger(bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("the patched spec can't be read: %w", err)
+	}
+	if !*patchDryRun {
+		return writeSwaggerFile(outputFile(filePath), patched)
+	}
+
+	fmt.Printf("%s\n  before: %s\n  after:  %s\n", pointer, formatPatchValue(previous), formatPatchValue(value))
+	problems, err := validateSpec(patched)
+	if err != nil {
+		return err
+	}
+	for _, problem := range problems {
+		fmt.Println("  " + problem)
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("the patched spec has %d problems; dry run, %s not written", len(problems), filePath)
+	}
+	fmt.Printf("The patched spec is valid; dry run, %s not written.\n", filePath)
+	return nil
+}
+
+// Split a JSON pointer into its unescaped reference tokens
+func pointerTokens(pointer string) []string {
+	if pointer == "" || pointer == "/" {
+		return nil
+	}
+	tokens := strings.Split(strings.TrimPrefix(pointer, "/"), "/")
+	for i, token := range tokens {
+		tokens[i] = strings.NewReplacer("~1", "/", "~0", "~").Replace(token)
+	}
+	return tokens
+}
+
+// Set the value at a path of tokens in a value decoded by yaml.v2,
+// returning the updated node and the value that was there before, or nil
+// when there wasn't one. Every token but the last must already exist.
+func setPointerValue(node interface{}, tokens []string, value interface{}) (interface{}, interface{}, error) {
+	if len(tokens) == 0 {
+		return value, node, nil
+	}
+	token, rest := tokens[0], tokens[1:]
+
+	switch v := node.(type) {
+	case map[interface{}]interface{}:
+		child, ok := v[token]
+		if !ok && len(rest) > 0 {
+			return nil, nil, fmt.Errorf("%q not found", token)
+		}
+		updated, previous, err := setPointerValue(child, rest, value)
+		if err != nil {
+			return nil, nil, err
+		}
+		v[token] = updated
+		return v, previous, nil
+	case []interface{}:
+		if token == "-" && len(rest) == 0 {
+			return append(v, value), nil, nil
+		}
+		i, err := strconv.Atoi(token)
+		if err != nil || i < 0 || i >= len(v) {
+			return nil, nil, fmt.Errorf("index %q is out of range", token)
+		}
+		updated, previous, err := setPointerValue(v[i], rest, value)
+		if err != nil {
+			return nil, nil, err
+		}
+		v[i] = updated
+		return v, previous, nil
+	}
+	return nil, nil, fmt.Errorf("can't set %q in a %s", token, describeYAMLKind(node))
+}
+
+// Name the kind of a scalar or missing value for error messages
+func describeYAMLKind(node interface{}) string {
+	if node == nil {
+		return "missing value"
+	}
+	return fmt.Sprintf("%T value", node)
+}
+
+// Format a value from a spec as compact JSON for the dry-run report
+func formatPatchValue(value interface{}) string {
+	if value == nil {
+		return "(none)"
+	}
+	normalized, err := normalizeYAMLValue(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	data, err := json.Marshal(normalized)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(data)
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"reflect"
+)
+
+// The parameters that apply to an operation: those shared on its path,
+// with the operation's own taking precedence over a shared parameter with
+// the same name and location
+func effectiveParameters(item PathItem, operation Operation) []Parameter {
+	if len(item.Parameters) == 0 {
+		return operation.Parameters
+	}
+
+	overridden := make(map[string]bool)
+	for _, param := range operation.Parameters {
+		overridden[param.In+":"+param.Name] = true
+	}
+	var params []Parameter
+	for _, param := range item.Parameters {
+		if !overridden[param.In+":"+param.Name] {
+			params = append(params, param)
+		}
+	}
+	return append(params, operation.Parameters...)
+}
+
+// Add a parameter shared by every operation on an existing path, such as an
+// ID used by GET, PUT and DELETE alike
+func addPathParameter(filePath, path string, reader *bufio.Reader) error {
+	unlock, err := lockFile(filePath)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	swagger, err := readSwaggerFile(filePath)
+	if err != nil {
+		return err
+	}
+	item, ok := swagger.Paths[path]
+	if !ok {
+		return fmt.Errorf("path %s not found", path)
+	}
+
+	param, err := promptParameter(reader)
+	if err != nil {
+		return err
+	}
+	for _, existing := range item.Parameters {
+		if existing.Name == param.Name && existing.In == param.In {
+			return fmt.Errorf("path %s already has a %s parameter %q", path, param.In, param.Name)
+		}
+	}
+
+	item.Parameters = append(item.Parameters, param)
+	swagger.Paths[path] = item
+	return writeSwaggerFile(outputFile(filePath), swagger)
+}
+
+// Merge the shared parameters of a path in another spec into those of the
+// base, returning a conflict for each one defined differently in both
+func mergePathParameters(base, other []Parameter, path string) ([]Parameter, []string) {
+	var conflicts []string
+	for _, param := range other {
+		found := false
+		for _, existing := range base {
+			if existing.Name != param.Name || existing.In != param.In {
+				continue
+			}
+			found = true
+			if !reflect.DeepEqual(existing, param) {
+				conflicts = append(conflicts, fmt.Sprintf("%s parameter %q of %s is defined differently in both files", param.In, param.Name, path))
+			}
+		}
+		if !found {
+			base = append(base, param)
+		}
+	}
+	return base, conflicts
+}
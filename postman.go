@@ -0,0 +1,392 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+const postmanSchemaURL = "https://schema.getpostman.com/json/collection/v2.1.0/collection.json"
+
+// The subset of the Postman Collection v2.1 format used for export and import
+type postmanCollection struct {
+	Info     postmanInfo       `json:"info"`
+	Item     []postmanItem     `json:"item"`
+	Variable []postmanVariable `json:"variable,omitempty"`
+}
+
+type postmanInfo struct {
+	Name   string `json:"name"`
+	Schema string `json:"schema"`
+}
+
+// A folder when Item is set, otherwise a single request
+type postmanItem struct {
+	Name     string            `json:"name"`
+	Item     []postmanItem     `json:"item,omitempty"`
+	Request  *postmanRequest   `json:"request,omitempty"`
+	Response []postmanResponse `json:"response,omitempty"`
+}
+
+type postmanRequest struct {
+	Method string          `json:"method"`
+	Header []postmanHeader `json:"header"`
+	URL    postmanURL      `json:"url"`
+	Body   *postmanBody    `json:"body,omitempty"`
+}
+
+type postmanHeader struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+type postmanURL struct {
+	Raw      string            `json:"raw"`
+	Host     []string          `json:"host,omitempty"`
+	Path     []string          `json:"path,omitempty"`
+	Variable []postmanVariable `json:"variable,omitempty"`
+}
+
+// Postman accepts a URL either as a plain string or as a structured object
+func (u *postmanURL) UnmarshalJSON(data []byte) error {
+	var raw string
+	if err := json.Unmarshal(data, &raw); err == nil {
+		*u = postmanURL{Raw: raw}
+		return nil
+	}
+
+	type plainURL postmanURL
+	return json.Unmarshal(data, (*plainURL)(u))
+}
+
+// A saved example response on a request
+type postmanResponse struct {
+	Name string `json:"name"`
+	Code int    `json:"code"`
+	Body string `json:"body"`
+}
+
+type postmanVariable struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+type postmanBody struct {
+	Mode    string                 `json:"mode"`
+	Raw     string                 `json:"raw"`
+	Options map[string]interface{} `json:"options,omitempty"`
+}
+
+// Export a Swagger YAML file as a Postman collection with a folder per
+// primary tag and a request per operation
+func exportPostman(filePath, outputPath string) error {
+	swagger, err := readSwaggerFile(filePath)
+	if err != nil {
+		return err
+	}
+
+	collection := buildPostmanCollection(swagger)
+	data, err := json.MarshalIndent(collection, "", "  ")
+	if *minifyOutput {
+		data, err = json.Marshal(collection)
+	}
+	if err != nil {
+		return err
+	}
+	if err := ioutil.WriteFile(outputPath, data, 0644); err != nil {
+		return err
+	}
+
+	fmt.Println("Postman collection exported successfully.")
+	return nil
+}
+
+// Convert a spec into a Postman collection. Untagged operations are placed
+// at the top level after the tag folders.
+func buildPostmanCollection(swagger *SwaggerTemplate) postmanCollection {
+	name, _ := swagger.Info["title"].(string)
+	collection := postmanCollection{
+		Info: postmanInfo{Name: name, Schema: postmanSchemaURL},
+		Item: []postmanItem{},
+	}
+	if len(swagger.Servers) > 0 {
+		collection.Variable = []postmanVariable{{Key: "baseUrl", Value: swagger.Servers[0].URL}}
+	}
+
+	folders := make(map[string][]postmanItem)
+	var untagged []postmanItem
+	for _, path := range sortedKeys(swagger.Paths) {
+		for _, method := range sortedKeys(swagger.Paths[path].Operations) {
+			operation := swagger.Paths[path].Operations[method]
+			item := postmanRequestItem(path, method, operation)
+			if len(operation.Tags) == 0 {
+				untagged = append(untagged, item)
+				continue
+			}
+			folders[operation.Tags[0]] = append(folders[operation.Tags[0]], item)
+		}
+	}
+
+	for _, tag := range sortedKeys(folders) {
+		collection.Item = append(collection.Item, postmanItem{Name: tag, Item: folders[tag]})
+	}
+	collection.Item = append(collection.Item, untagged...)
+	return collection
+}
+
+// Build the Postman request for a single operation
+func postmanRequestItem(path, method string, operation Operation) postmanItem {
+	name := operation.Summary
+	if name == "" {
+		name = strings.ToUpper(method) + " " + path
+	}
+
+	// Postman writes path parameters as :name rather than {name}
+	url := postmanURL{Host: []string{"{{baseUrl}}"}}
+	for _, segment := range strings.Split(strings.Trim(path, "/"), "/") {
+		if strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}") {
+			param := strings.Trim(segment, "{}")
+			segment = ":" + param
+			url.Variable = append(url.Variable, postmanVariable{Key: param})
+		}
+		if segment != "" {
+			url.Path = append(url.Path, segment)
+		}
+	}
+	url.Raw = "{{baseUrl}}/" + strings.Join(url.Path, "/")
+
+	// An operation-level server replaces the collection's base URL
+	if len(operation.Servers) > 0 {
+		base := strings.TrimSuffix(operation.Servers[0].URL, "/")
+		url.Host = []string{base}
+		url.Raw = base + "/" + strings.Join(url.Path, "/")
+	}
+
+	request := &postmanRequest{
+		Method: strings.ToUpper(method),
+		Header: []postmanHeader{},
+		URL:    url,
+	}
+	if operation.RequestBody != nil {
+		if example, ok := requestBodyExample(*operation.RequestBody); ok {
+			if raw, err := json.MarshalIndent(example, "", "  "); err == nil {
+				request.Header = append(request.Header, postmanHeader{Key: "Content-Type", Value: "application/json"})
+				request.Body = &postmanBody{
+					Mode:    "raw",
+					Raw:     string(raw),
+					Options: map[string]interface{}{"raw": map[string]string{"language": "json"}},
+				}
+			}
+		}
+	}
+
+	return postmanItem{Name: name, Request: request}
+}
+
+// Find an example for a JSON request body, derived from its schema examples
+func requestBodyExample(body RequestBody) (interface{}, bool) {
+	media, ok := body.Content["application/json"]
+	if !ok {
+		return nil, false
+	}
+	return schemaExample(media.Schema)
+}
+
+// Assemble an example value from the examples on a schema and its
+// properties or items. Reports false when no examples are present.
+func schemaExample(schema Schema) (interface{}, bool) {
+	if schema.Example != nil {
+		example, err := normalizeYAMLValue(schema.Example)
+		return example, err == nil
+	}
+
+	switch schema.Type {
+	case "object":
+		example := make(map[string]interface{})
+		for name, prop := range schema.Properties {
+			if value, ok := schemaExample(prop); ok {
+				example[name] = value
+			}
+		}
+		return example, len(example) > 0
+	case "array":
+		if schema.Items == nil {
+			return nil, false
+		}
+		if item, ok := schemaExample(*schema.Items); ok {
+			return []interface{}{item}, true
+		}
+	}
+	return nil, false
+}
+
+// Matches the scheme and host, or a {{variable}} standing in for them, at
+// the start of a raw Postman URL
+var postmanHostPattern = regexp.MustCompile(`^(?:[a-zA-Z][a-zA-Z0-9+.-]*://[^/]*|\{\{[^}]*\}\}[^/]*)`)
+
+// Scaffold a new Swagger YAML file from a Postman collection. Each request
+// becomes an operation tagged with its folder name, with schemas inferred
+// from its example body and saved responses.
+func importPostman(collectionPath, filePath string) error {
+	data, err := ioutil.ReadFile(collectionPath)
+	if err != nil {
+		return err
+	}
+	var collection postmanCollection
+	if err := json.Unmarshal(data, &collection); err != nil {
+		return err
+	}
+
+	swagger := newSwaggerTemplate()
+	if collection.Info.Name != "" {
+		swagger.Info["title"] = collection.Info.Name
+	}
+
+	imported, skipped := importPostmanItems(swagger, collection.Item, "")
+	if err := checkPathLimit(swagger, 0); err != nil {
+		return err
+	}
+	fmt.Printf("Imported %d requests from the Postman collection.\n", imported)
+	if len(skipped) > 0 {
+		fmt.Printf("Could not map %d requests:\n", len(skipped))
+		for _, reason := range skipped {
+			fmt.Println("  " + reason)
+		}
+	}
+
+	return writeSwaggerFile(outputFile(filePath), swagger)
+}
+
+// Import the requests in a list of items, descending into folders. Returns
+// the number of requests imported and a description of each one skipped.
+func importPostmanItems(swagger *SwaggerTemplate, items []postmanItem, folder string) (int, []string) {
+	imported := 0
+	var skipped []string
+	for _, item := range items {
+		if item.Request == nil {
+			count, skips := importPostmanItems(swagger, item.Item, item.Name)
+			imported += count
+			skipped = append(skipped, skips...)
+			continue
+		}
+
+		if err := importPostmanRequest(swagger, item, folder); err != nil {
+			skipped = append(skipped, fmt.Sprintf("%s: %v", item.Name, err))
+			continue
+		}
+		imported++
+	}
+	return imported, skipped
+}
+
+// Add a single Postman request to the spec
+func importPostmanRequest(swagger *SwaggerTemplate, item postmanItem, folder string) error {
+	request := item.Request
+	if request.Method == "" {
+		return fmt.Errorf("request has no method")
+	}
+	path, params := postmanPath(request.URL)
+	if path == "" {
+		return fmt.Errorf("request has no URL")
+	}
+	path = prefixImportedPath(path)
+	method := strings.ToLower(request.Method)
+
+	operation := Operation{
+		Summary:    item.Name,
+		Parameters: params,
+		Responses:  make(map[string]Response),
+	}
+	if folder != "" {
+		operation.Tags = []string{folder}
+	}
+
+	if request.Body != nil && request.Body.Mode == "raw" && strings.TrimSpace(request.Body.Raw) != "" {
+		schema, err := inferSampleSchema(request.Body.Raw)
+		if err != nil {
+			return fmt.Errorf("request body: %w", err)
+		}
+		operation.RequestBody = &RequestBody{
+			Content: map[string]MediaType{"application/json": {Schema: schema}},
+		}
+	}
+
+	for _, saved := range item.Response {
+		status := strconv.Itoa(saved.Code)
+		response := Response{Description: http.StatusText(saved.Code)}
+		if saved.Code == 0 {
+			status = "default"
+			response.Description = "Default response"
+		}
+		if strings.TrimSpace(saved.Body) != "" {
+			schema, err := inferSampleSchema(saved.Body)
+			if err != nil {
+				return fmt.Errorf("response %q: %w", saved.Name, err)
+			}
+			response.Content = map[string]MediaType{"application/json": {Schema: schema}}
+		}
+		operation.Responses[status] = response
+	}
+	if len(operation.Responses) == 0 {
+		operation.Responses["200"] = Response{Description: "Successful response"}
+	}
+
+	ensurePathItem(swagger, path)
+	swagger.Paths[path].Operations[method] = operation
+	return nil
+}
+
+// Infer a schema from a JSON payload stored as text
+func inferSampleSchema(text string) (Schema, error) {
+	var sample interface{}
+	if err := decodeJSON([]byte(text), &sample); err != nil {
+		return Schema{}, err
+	}
+	return inferSchema(sample, "", 0)
+}
+
+// Work out the OpenAPI path of a Postman URL, converting :param and
+// {{variable}} segments to {param} templates and declaring them as path
+// parameters
+func postmanPath(url postmanURL) (string, []Parameter) {
+	segments := url.Path
+	if len(segments) == 0 {
+		raw := postmanHostPattern.ReplaceAllString(url.Raw, "")
+		if i := strings.IndexAny(raw, "?#"); i >= 0 {
+			raw = raw[:i]
+		}
+		if raw == "" && url.Raw == "" {
+			return "", nil
+		}
+		segments = strings.Split(strings.Trim(raw, "/"), "/")
+	}
+
+	var params []Parameter
+	var pathSegments []string
+	for _, segment := range segments {
+		name := ""
+		switch {
+		case strings.HasPrefix(segment, ":"):
+			name = strings.TrimPrefix(segment, ":")
+		case strings.HasPrefix(segment, "{{") && strings.HasSuffix(segment, "}}"):
+			name = strings.TrimSuffix(strings.TrimPrefix(segment, "{{"), "}}")
+		}
+		if name != "" {
+			segment = "{" + name + "}"
+			params = append(params, Parameter{
+				Name:     name,
+				In:       "path",
+				Required: true,
+				Schema:   &Schema{Type: "string"},
+			})
+		}
+		if segment != "" {
+			pathSegments = append(pathSegments, segment)
+		}
+	}
+	return "/" + strings.Join(pathSegments, "/"), params
+}
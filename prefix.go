@@ -0,0 +1,47 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+)
+
+var (
+	importPrefix      = flag.String("prefix", "", "have import-har and import-postman prepend this base path, e.g. /api/v1, to every imported path; applied after -strip-prefix")
+	importStripPrefix = flag.String("strip-prefix", "", "have import-har and import-postman remove this base path, e.g. /api/v1, from the start of every imported path")
+)
+
+// Apply -strip-prefix and then -prefix to a path generated by an import.
+// The stripped prefix must match whole segments, so /api/v1 is stripped
+// from /api/v1/pets but not from /api/v10/pets; a path it doesn't match is
+// kept as it is, with a warning.
+func prefixImportedPath(path string) string {
+	if strip := normalizeBasePath(*importStripPrefix); strip != "" {
+		switch {
+		case path == strip:
+			path = "/"
+		case strings.HasPrefix(path, strip+"/"):
+			path = strings.TrimPrefix(path, strip)
+		default:
+			fmt.Printf("Warning: %s doesn't start with -strip-prefix %s, keeping it as it is\n", path, strip)
+		}
+	}
+
+	if prefix := normalizeBasePath(*importPrefix); prefix != "" {
+		if path == "/" {
+			return prefix
+		}
+		path = prefix + path
+	}
+	return path
+}
+
+// Give a base path a leading slash and no trailing one, or return "" for
+// an empty or root base path
+func normalizeBasePath(base string) string {
+	base = strings.Trim(base, "/")
+	if base == "" {
+		return ""
+	}
+	return "/" + base
+}
@@ -0,0 +1,37 @@
+package main
+
+import (
+	"flag"
+	"strings"
+)
+
+const problemJSONMediaType = "application/problem+json"
+
+var problemDetails = flag.Bool("problem-details", false, "have update document 4xx and 5xx responses as RFC 7807 problem details under application/problem+json")
+
+// Report whether a status code is a client or server error
+func isErrorStatus(status string) bool {
+	return strings.HasPrefix(status, "4") || strings.HasPrefix(status, "5")
+}
+
+// Build an RFC 7807 problem details schema, merging in any extra
+// properties from a schema generated from a sample. The standard members
+// keep their standard types.
+func problemDetailsSchema(sample Schema) Schema {
+	schema := Schema{
+		Type: "object",
+		Properties: map[string]Schema{
+			"type":     {Type: "string", Format: "uri"},
+			"title":    {Type: "string"},
+			"status":   {Type: "integer"},
+			"detail":   {Type: "string"},
+			"instance": {Type: "string", Format: "uri"},
+		},
+	}
+	for name, prop := range sample.Properties {
+		if _, standard := schema.Properties[name]; !standard {
+			schema.Properties[name] = prop
+		}
+	}
+	return schema
+}
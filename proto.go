@@ -0,0 +1,513 @@
+package main
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"strings"
+)
+
+// The parts of a compiled FileDescriptorSet (protoc --descriptor_set_out)
+// needed to describe messages. Field numbers in the comments refer to
+// google/protobuf/descriptor.proto.
+type protoFile struct {
+	name       string         // 1
+	pkg        string         // 2
+	messages   []protoMessage // 4
+	enums      []protoEnum    // 5
+	services   int            // 6
+	extensions int            // 7
+}
+
+type protoMessage struct {
+	name       string                 // 1
+	fields     []protoFieldDescriptor // 2
+	nested     []protoMessage         // 3
+	enums      []protoEnum            // 4
+	extensions int                    // 6
+	mapEntry   bool                   // 7, MessageOptions.map_entry
+	oneofs     []string               // 8
+}
+
+type protoFieldDescriptor struct {
+	name           string // 1
+	label          uint64 // 4
+	typ            uint64 // 5
+	typeName       string // 6
+	oneofIndex     int    // 9, -1 when not in a oneof
+	jsonName       string // 10
+	proto3Optional bool   // 17
+}
+
+type protoEnum struct {
+	name   string   // 1
+	values []string // 2, EnumValueDescriptorProto.name
+}
+
+// Field labels and the types that aren't scalars
+const (
+	protoLabelRequired = 2
+	protoLabelRepeated = 3
+	protoTypeGroup     = 10
+	protoTypeMessage   = 11
+	protoTypeEnum      = 14
+)
+
+// Scalar field types and the schemas their JSON mapping has. 64-bit
+// integers are strings in JSON, since they don't fit in a double.
+var protoScalars = map[uint64]Schema{
+	1:  {Type: "number", Format: "double"}, // double
+	2:  {Type: "number", Format: "float"},  // float
+	3:  {Type: "string", Format: "int64"},  // int64
+	4:  {Type: "string", Format: "uint64"}, // uint64
+	5:  {Type: "integer", Format: "int32"}, // int32
+	6:  {Type: "string", Format: "uint64"}, // fixed64
+	7:  {Type: "integer", Format: "int64"}, // fixed32
+	8:  {Type: "boolean"},                  // bool
+	9:  {Type: "string"},                   // string
+	12: {Type: "string", Format: "byte"},   // bytes
+	13: {Type: "integer", Format: "int64"}, // uint32
+	15: {Type: "integer", Format: "int32"}, // sfixed32
+	16: {Type: "string", Format: "int64"},  // sfixed64
+	17: {Type: "integer", Format: "int32"}, // sint32
+	18: {Type: "string", Format: "int64"},  // sint64
+}
+
+// Well-known types and the schemas of their JSON mapping
+var protoWellKnownTypes = map[string]Schema{
+	".google.protobuf.Timestamp":   {Type: "string", Format: "date-time"},
+	".google.protobuf.Duration":    {Type: "string"},
+	".google.protobuf.FieldMask":   {Type: "string"},
+	".google.protobuf.Empty":       {Type: "object"},
+	".google.protobuf.Struct":      {Type: "object"},
+	".google.protobuf.Value":       {},
+	".google.protobuf.ListValue":   {Type: "array", Items: &Schema{}},
+	".google.protobuf.Any":         {Type: "object", Properties: map[string]Schema{"@type": {Type: "string"}}},
+	".google.protobuf.DoubleValue": {Type: "number", Format: "double", Nullable: true},
+	".google.protobuf.FloatValue":  {Type: "number", Format: "float", Nullable: true},
+	".google.protobuf.Int64Value":  {Type: "string", Format: "int64", Nullable: true},
+	".google.protobuf.UInt64Value": {Type: "string", Format: "uint64", Nullable: true},
+	".google.protobuf.Int32Value":  {Type: "integer", Format: "int32", Nullable: true},
+	".google.protobuf.UInt32Value": {Type: "integer", Format: "int64", Nullable: true},
+	".google.protobuf.BoolValue":   {Type: "boolean", Nullable: true},
+	".google.protobuf.StringValue": {Type: "string", Nullable: true},
+	".google.protobuf.BytesValue":  {Type: "string", Format: "byte", Nullable: true},
+}
+
+// Converts the messages and enums of a descriptor set to component schemas
+type protoImporter struct {
+	names      map[string]string        // fully-qualified type name to component name
+	mapEntries map[string]*protoMessage // the synthetic messages of map fields
+	warnings   []string
+}
+
+// A message or enum to convert, with its fully-qualified name
+type protoType struct {
+	fullName string
+	message  *protoMessage
+	enum     *protoEnum
+}
+
+// Add a component schema to a spec for each message and enum in a compiled
+// FileDescriptorSet. Services are not turned into paths; they and other
+// unsupported features are reported and skipped.
+func importProto(filePath, descriptorPath string) error {
+	unlock, err := lockFile(filePath)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	swagger, err := readSwaggerFile(filePath)
+	if err != nil {
+		return err
+	}
+	data, err := ioutil.ReadFile(descriptorPath)
+	if err != nil {
+		return err
+	}
+	files, err := parseFileDescriptorSet(data)
+	if err != nil {
+		return fmt.Errorf("%s is not a FileDescriptorSet: %w", descriptorPath, err)
+	}
+
+	p := &protoImporter{
+		names:      make(map[string]string),
+		mapEntries: make(map[string]*protoMessage),
+	}
+	var types []protoType
+	for _, file := range files {
+		// Well-known types map to their JSON forms rather than components
+		if file.pkg == "google.protobuf" {
+			continue
+		}
+		if file.services > 0 {
+			p.warnings = append(p.warnings, fmt.Sprintf("%s: %d services skipped, only messages and enums are imported", file.name, file.services))
+		}
+		if file.extensions > 0 {
+			p.warnings = append(p.warnings, fmt.Sprintf("%s: extensions are not supported and were skipped", file.name))
+		}
+		prefix := "."
+		if file.pkg != "" {
+			prefix += file.pkg + "."
+		}
+		types = append(types, p.collectTypes(prefix, "", file.messages, file.enums)...)
+	}
+
+	if swagger.Components.Schemas == nil {
+		swagger.Components.Schemas = make(map[string]Schema)
+	}
+	for _, t := range types {
+		name := p.names[t.fullName]
+		if t.enum != nil {
+			schema := Schema{Type: "string"}
+			for _, value := range t.enum.values {
+				schema.Enum = append(schema.Enum, value)
+			}
+			swagger.Components.Schemas[name] = schema
+		} else {
+			swagger.Components.Schemas[name] = p.messageSchema(name, t.message)
+		}
+	}
+
+	fmt.Printf("Imported %d protobuf messages and enums as component schemas.\n", len(types))
+	for _, warning := range p.warnings {
+		fmt.Println("Warning:", warning)
+	}
+	return writeSwaggerFile(outputFile(filePath), swagger)
+}
+
+// Name the messages and enums declared in a scope, and those nested in
+// them, returning them in declaration order. Components are named without
+// the package, e.g. Pet.Tag for .pets.v1.Pet.Tag, unless that name is
+// already taken by a type from another package.
+func (p *protoImporter) collectTypes(prefix, scope string, messages []protoMessage, enums []protoEnum) []protoType {
+	var types []protoType
+	for i := range enums {
+		types = append(types, protoType{fullName: prefix + scope + enums[i].name, enum: &enums[i]})
+		p.nameType(prefix, scope+enums[i].name)
+	}
+	for i := range messages {
+		message := &messages[i]
+		fullName := prefix + scope + message.name
+		if message.mapEntry {
+			p.mapEntries[fullName] = message
+			continue
+		}
+		types = append(types, protoType{fullName: fullName, message: message})
+		p.nameType(prefix, scope+message.name)
+		types = append(types, p.collectTypes(prefix, scope+message.name+".", message.nested, message.enums)...)
+	}
+	return types
+}
+
+// Pick the component name of a type
+func (p *protoImporter) nameType(prefix, relativeName string) {
+	name := relativeName
+	for _, taken := range p.names {
+		if taken == name {
+			name = strings.TrimPrefix(prefix, ".") + relativeName
+			p.warnings = append(p.warnings, fmt.Sprintf("%s: %s is already used by another package, named it %s", prefix+relativeName, relativeName, name))
+			break
+		}
+	}
+	p.names[prefix+relativeName] = name
+}
+
+// Build the schema of a message. Fields are named by their JSON names, and
+// proto2 required fields are required.
+func (p *protoImporter) messageSchema(name string, message *protoMessage) Schema {
+	schema := Schema{Type: "object", Properties: make(map[string]Schema)}
+	if message.extensions > 0 {
+		p.warnings = append(p.warnings, fmt.Sprintf("%s: extensions are not supported and were skipped", name))
+	}
+
+	oneofFields := make(map[int][]string)
+	for _, field := range message.fields {
+		fieldName := field.jsonName
+		if fieldName == "" {
+			fieldName = field.name
+		}
+		fieldSchema, err := p.fieldSchema(field)
+		if err != nil {
+			p.warnings = append(p.warnings, fmt.Sprintf("%s.%s: %v", name, field.name, err))
+			continue
+		}
+		schema.Properties[fieldName] = fieldSchema
+		if field.label == protoLabelRequired {
+			schema.Required = append(schema.Required, fieldName)
+		}
+		if field.oneofIndex >= 0 && !field.proto3Optional {
+			oneofFields[field.oneofIndex] = append(oneofFields[field.oneofIndex], fieldName)
+		}
+	}
+
+	for index, oneof := range message.oneofs {
+		if fields := oneofFields[index]; len(fields) > 0 {
+			p.warnings = append(p.warnings, fmt.Sprintf("%s: oneof %s is documented as separate optional properties %s", name, oneof, strings.Join(fields, ", ")))
+		}
+	}
+	return schema
+}
+
+// Build the schema of a field: its type, wrapped in an array when repeated,
+// or an object of values for map fields
+func (p *protoImporter) fieldSchema(field protoFieldDescriptor) (Schema, error) {
+	if entry, ok := p.mapEntries[field.typeName]; ok && field.typ == protoTypeMessage {
+		for _, entryField := range entry.fields {
+			if entryField.name == "value" {
+				value, err := p.typeSchema(entryField)
+				if err != nil {
+					return Schema{}, err
+				}
+				return Schema{Type: "object", AdditionalProperties: &AdditionalProperties{Schema: &value}}, nil
+			}
+		}
+		return Schema{}, fmt.Errorf("map entry %s has no value field", field.typeName)
+	}
+
+	schema, err := p.typeSchema(field)
+	if err != nil {
+		return Schema{}, err
+	}
+	if field.label == protoLabelRepeated {
+		return Schema{Type: "array", Items: &schema}, nil
+	}
+	return schema, nil
+}
+
+// Map a field's type to a schema: a scalar type, a well-known type, or a
+// reference to the component of a message or enum
+func (p *protoImporter) typeSchema(field protoFieldDescriptor) (Schema, error) {
+	switch field.typ {
+	case protoTypeMessage, protoTypeEnum:
+		if schema, ok := protoWellKnownTypes[field.typeName]; ok {
+			return schema, nil
+		}
+		name, ok := p.names[field.typeName]
+		if !ok {
+			return Schema{}, fmt.Errorf("type %s is not in the descriptor set; build it with --include_imports", field.typeName)
+		}
+		return Schema{Ref: schemaRefPrefix + name}, nil
+	case protoTypeGroup:
+		return Schema{}, errors.New("groups are not supported")
+	}
+	schema, ok := protoScalars[field.typ]
+	if !ok {
+		return Schema{}, fmt.Errorf("unknown field type %d", field.typ)
+	}
+	return schema, nil
+}
+
+// A field of an encoded protobuf message
+type protoWireField struct {
+	number int
+	value  uint64 // varint and fixed-size fields
+	data   []byte // length-delimited fields
+}
+
+// Split an encoded protobuf message into its fields
+func decodeProtoMessage(data []byte) ([]protoWireField, error) {
+	var fields []protoWireField
+	for len(data) > 0 {
+		key, n := binary.Uvarint(data)
+		if n <= 0 {
+			return nil, errors.New("malformed field key")
+		}
+		data = data[n:]
+		field := protoWireField{number: int(key >> 3)}
+		switch wireType := key & 7; wireType {
+		case 0:
+			if field.value, n = binary.Uvarint(data); n <= 0 {
+				return nil, errors.New("malformed varint")
+			}
+			data = data[n:]
+		case 1:
+			if len(data) < 8 {
+				return nil, errors.New("truncated fixed64")
+			}
+			field.value, data = binary.LittleEndian.Uint64(data), data[8:]
+		case 2:
+			length, n := binary.Uvarint(data)
+			if n <= 0 || length > uint64(len(data)-n) {
+				return nil, errors.New("truncated length-delimited field")
+			}
+			field.data, data = data[n:n+int(length)], data[n+int(length):]
+		case 5:
+			if len(data) < 4 {
+				return nil, errors.New("truncated fixed32")
+			}
+			field.value, data = uint64(binary.LittleEndian.Uint32(data)), data[4:]
+		default:
+			return nil, fmt.Errorf("unsupported wire type %d", wireType)
+		}
+		fields = append(fields, field)
+	}
+	return fields, nil
+}
+
+// Decode a FileDescriptorSet, whose field 1 holds the files
+func parseFileDescriptorSet(data []byte) ([]protoFile, error) {
+	fields, err := decodeProtoMessage(data)
+	if err != nil {
+		return nil, err
+	}
+	var files []protoFile
+	for _, field := range fields {
+		if field.number != 1 {
+			continue
+		}
+		file, err := parseProtoFile(field.data)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, file)
+	}
+	if len(files) == 0 {
+		return nil, errors.New("no files found")
+	}
+	return files, nil
+}
+
+func parseProtoFile(data []byte) (protoFile, error) {
+	var file protoFile
+	fields, err := decodeProtoMessage(data)
+	if err != nil {
+		return file, err
+	}
+	for _, field := range fields {
+		switch field.number {
+		case 1:
+			file.name = string(field.data)
+		case 2:
+			file.pkg = string(field.data)
+		case 4:
+			message, err := parseProtoMessage(field.data)
+			if err != nil {
+				return file, err
+			}
+			file.messages = append(file.messages, message)
+		case 5:
+			enum, err := parseProtoEnum(field.data)
+			if err != nil {
+				return file, err
+			}
+			file.enums = append(file.enums, enum)
+		case 6:
+			file.services++
+		case 7:
+			file.extensions++
+		}
+	}
+	return file, nil
+}
+
+func parseProtoMessage(data []byte) (protoMessage, error) {
+	var message protoMessage
+	fields, err := decodeProtoMessage(data)
+	if err != nil {
+		return message, err
+	}
+	for _, field := range fields {
+		switch field.number {
+		case 1:
+			message.name = string(field.data)
+		case 2:
+			descriptor, err := parseProtoField(field.data)
+			if err != nil {
+				return message, err
+			}
+			message.fields = append(message.fields, descriptor)
+		case 3:
+			nested, err := parseProtoMessage(field.data)
+			if err != nil {
+				return message, err
+			}
+			message.nested = append(message.nested, nested)
+		case 4:
+			enum, err := parseProtoEnum(field.data)
+			if err != nil {
+				return message, err
+			}
+			message.enums = append(message.enums, enum)
+		case 6:
+			message.extensions++
+		case 7:
+			options, err := decodeProtoMessage(field.data)
+			if err != nil {
+				return message, err
+			}
+			for _, option := range options {
+				if option.number == 7 {
+					message.mapEntry = option.value != 0
+				}
+			}
+		case 8:
+			oneof, err := decodeProtoMessage(field.data)
+			if err != nil {
+				return message, err
+			}
+			name := ""
+			for _, part := range oneof {
+				if part.number == 1 {
+					name = string(part.data)
+				}
+			}
+			message.oneofs = append(message.oneofs, name)
+		}
+	}
+	return message, nil
+}
+
+func parseProtoField(data []byte) (protoFieldDescriptor, error) {
+	descriptor := protoFieldDescriptor{oneofIndex: -1}
+	fields, err := decodeProtoMessage(data)
+	if err != nil {
+		return descriptor, err
+	}
+	for _, field := range fields {
+		switch field.number {
+		case 1:
+			descriptor.name = string(field.data)
+		case 4:
+			descriptor.label = field.value
+		case 5:
+			descriptor.typ = field.value
+		case 6:
+			descriptor.typeName = string(field.data)
+		case 9:
+			descriptor.oneofIndex = int(field.value)
+		case 10:
+			descriptor.jsonName = string(field.data)
+		case 17:
+			descriptor.proto3Optional = field.value != 0
+		}
+	}
+	return descriptor, nil
+}
+
+func parseProtoEnum(data []byte) (protoEnum, error) {
+	var enum protoEnum
+	fields, err := decodeProtoMessage(data)
+	if err != nil {
+		return enum, err
+	}
+	for _, field := range fields {
+		switch field.number {
+		case 1:
+			enum.name = string(field.data)
+		case 2:
+			value, err := decodeProtoMessage(field.data)
+			if err != nil {
+				return enum, err
+			}
+			for _, part := range value {
+				if part.number == 1 {
+					enum.values = append(enum.values, string(part.data))
+				}
+			}
+		}
+	}
+	return enum, nil
+}
@@ -0,0 +1,106 @@
+package main
+
+import "flag"
+
+var detectRecursion = flag.Bool("detect-recursion", false, "with -extract-component, replace nested objects shaped like an object enclosing them with a $ref to that object's component, so tree-like samples give recursive schemas")
+
+// An object on the way down to the schema being visited, and the name of
+// the component nested copies of it refer to once one is found. The shape
+// is taken before any of the object's properties are replaced, so that
+// later siblings of a replaced property are still compared with the
+// original.
+type recursionAncestor struct {
+	shape map[string]string
+	base  string
+	name  string
+}
+
+// Replace nested objects in a component schema that have the same shape as
+// an object enclosing them with a $ref to that object. The component named
+// name is referenced directly; enclosing objects below it that turn out to
+// be recursive are extracted into components named after their property,
+// with an Item suffix for array items.
+func extractRecursiveComponents(swagger *SwaggerTemplate, name string) {
+	schema := swagger.Components.Schemas[name]
+	root := &recursionAncestor{shape: objectShape(schema), name: name}
+	swagger.Components.Schemas[name] = replaceRecursiveSchemas(swagger, schema, []*recursionAncestor{root})
+}
+
+// Replace the recursive positions in a schema's properties and array items.
+// ancestors holds the objects enclosing schema, outermost first, with
+// schema itself last when it's an object.
+func replaceRecursiveSchemas(swagger *SwaggerTemplate, schema Schema, ancestors []*recursionAncestor) Schema {
+	// Sorted so that component names resolve the same way every run
+	for _, name := range sortedKeys(schema.Properties) {
+		schema.Properties[name] = replaceRecursiveSchema(swagger, schema.Properties[name], capitalize(name), ancestors)
+	}
+	if schema.Items != nil {
+		items := replaceRecursiveSchema(swagger, *schema.Items, "Item", ancestors)
+		schema.Items = &items
+	}
+	return schema
+}
+
+// Replace a nested schema with a $ref when it's shaped like one of its
+// ancestors, or else replace the recursive positions within it, using base
+// to name its component should it become recursive itself
+func replaceRecursiveSchema(swagger *SwaggerTemplate, schema Schema, base string, ancestors []*recursionAncestor) Schema {
+	if schema.Type == "array" && schema.Items != nil {
+		items := replaceRecursiveSchema(swagger, *schema.Items, base+"Item", ancestors)
+		schema.Items = &items
+		return schema
+	}
+	if schema.Type != "object" || len(schema.Properties) == 0 {
+		return schema
+	}
+
+	for _, ancestor := range ancestors {
+		if !hasObjectShape(schema, ancestor.shape) {
+			continue
+		}
+		if ancestor.name == "" {
+			if !isValidComponentName(ancestor.base) {
+				continue
+			}
+			ancestor.name = reserveComponentName(swagger, ancestor.base)
+		}
+		return Schema{Ref: schemaRefPrefix + ancestor.name}
+	}
+
+	self := &recursionAncestor{shape: objectShape(schema), base: base}
+	schema = replaceRecursiveSchemas(swagger, schema, append(ancestors[:len(ancestors):len(ancestors)], self))
+	if self.name == "" {
+		return schema
+	}
+	swagger.Components.Schemas[self.name] = schema
+	return Schema{Ref: schemaRefPrefix + self.name}
+}
+
+// The property names of an object schema with the type of each, or nil for
+// schemas that aren't objects
+func objectShape(schema Schema) map[string]string {
+	if schema.Type != "object" {
+		return nil
+	}
+	shape := make(map[string]string, len(schema.Properties))
+	for name, prop := range schema.Properties {
+		shape[name] = prop.Type
+	}
+	return shape
+}
+
+// Report whether an object schema has the same properties with the same
+// types as a shape taken by objectShape. The items of array properties
+// aren't compared, since the innermost node of a tree sample usually has an
+// empty array where the nodes above it have children.
+func hasObjectShape(schema Schema, shape map[string]string) bool {
+	if schema.Type != "object" || shape == nil || len(schema.Properties) != len(shape) {
+		return false
+	}
+	for name, prop := range schema.Properties {
+		if schemaType, ok := shape[name]; !ok || schemaType != prop.Type {
+			return false
+		}
+	}
+	return true
+}
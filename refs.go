@@ -0,0 +1,46 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Find every internal $ref that doesn't resolve to a component schema,
+// returning a description of each with its location. External refs, to
+// other files or URLs, aren't checked.
+func danglingRefs(swagger *SwaggerTemplate) []string {
+	var problems []string
+	walkSpecSchemas(swagger, func(schema *Schema, location string) {
+		if schema.Ref == "" || !strings.HasPrefix(schema.Ref, "#") {
+			return
+		}
+		if !strings.HasPrefix(schema.Ref, schemaRefPrefix) {
+			problems = append(problems, fmt.Sprintf("%s: $ref %s doesn't point to a component schema", location, schema.Ref))
+			return
+		}
+		name := strings.NewReplacer("~1", "/", "~0", "~").Replace(strings.TrimPrefix(schema.Ref, schemaRefPrefix))
+		if _, ok := swagger.Components.Schemas[name]; !ok {
+			problems = append(problems, fmt.Sprintf("%s: $ref %s not found", location, schema.Ref))
+		}
+	})
+	return problems
+}
+
+// Report the dangling internal $refs in a spec file
+func checkRefs(filePath string) error {
+	swagger, err := readSwaggerFile(filePath)
+	if err != nil {
+		return err
+	}
+
+	problems := danglingRefs(swagger)
+	for _, problem := range problems {
+		fmt.Println("  " + problem)
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("%s has %d dangling refs", filePath, len(problems))
+	}
+
+	fmt.Println("All refs resolve.")
+	return nil
+}
@@ -0,0 +1,51 @@
+package main
+
+import "fmt"
+
+// Rename a component schema and rewrite every $ref that points at it
+func renameSchema(filePath, oldName, newName string) error {
+	unlock, err := lockFile(filePath)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	swagger, err := readSwaggerFile(filePath)
+	if err != nil {
+		return err
+	}
+
+	updated, err := renameComponentSchema(swagger, oldName, newName)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Renamed %s to %s and updated %d references.\n", oldName, newName, updated)
+
+	return writeSwaggerFile(outputFile(filePath), swagger)
+}
+
+// Rename a component schema in place, returning how many refs were updated
+func renameComponentSchema(swagger *SwaggerTemplate, oldName, newName string) (int, error) {
+	schema, exists := swagger.Components.Schemas[oldName]
+	if !exists {
+		return 0, fmt.Errorf("component schema %q not found", oldName)
+	}
+	if _, taken := swagger.Components.Schemas[newName]; taken {
+		return 0, fmt.Errorf("component schema %q already exists", newName)
+	}
+	if !isValidComponentName(newName) {
+		return 0, fmt.Errorf("invalid component name %q: use letters, digits, '.', '-' and '_' only", newName)
+	}
+
+	delete(swagger.Components.Schemas, oldName)
+	swagger.Components.Schemas[newName] = schema
+
+	updated := 0
+	walkSpecSchemas(swagger, func(schema *Schema, location string) {
+		if schema.Ref == schemaRefPrefix+oldName {
+			schema.Ref = schemaRefPrefix + newName
+			updated++
+		}
+	})
+	return updated, nil
+}
@@ -0,0 +1,193 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// A flag that can be given more than once, collecting every value
+type stringList []string
+
+func (l *stringList) String() string {
+	return strings.Join(*l, ", ")
+}
+
+func (l *stringList) Set(value string) error {
+	*l = append(*l, value)
+	return nil
+}
+
+var (
+	jsonSamples      stringList
+	inferArrayBounds = flag.Bool("infer-array-bounds", false, "when update infers a schema from more than one -json sample, set minItems and maxItems on arrays to the shortest and longest lengths observed; the bounds only reflect the samples, so review and edit them as needed")
+	exactArrayBounds = flag.Bool("exact-array-bounds", false, "with -infer-array-bounds and a single sample, set both minItems and maxItems to each array's length")
+	describeRanges   = flag.Bool("describe-ranges", false, "when update infers a schema from more than one -json sample, describe each numeric property with the range of values observed, e.g. \"observed range 0–100\"")
+)
+
+func init() {
+	flag.Var(&jsonSamples, "json", "JSON sample file for update to infer the schema from instead of prompting; repeat to union several samples, requiring only the fields present in all of them")
+}
+
+// Infer one schema from several JSON sample files. Properties found in any
+// sample are included, and those found in every sample are required. The
+// first sample is returned too, for use as the example.
+func sampleFilesSchema(paths []string) (Schema, map[string]interface{}, error) {
+	var merged Schema
+	var first map[string]interface{}
+	ranges := make(map[string]*observedRange)
+	samples := make([]interface{}, 0, len(paths))
+	for i, path := range paths {
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return Schema{}, nil, err
+		}
+		var sample map[string]interface{}
+		if err := decodeJSON(data, &sample); err != nil {
+			return Schema{}, nil, fmt.Errorf("%s: %w", path, err)
+		}
+		schema, err := generateSchema(sample)
+		if err != nil {
+			return Schema{}, nil, fmt.Errorf("%s: %w", path, err)
+		}
+		samples = append(samples, sample)
+		if len(paths) > 1 {
+			requireAllProperties(&schema)
+			observeRanges(sample, "", ranges)
+		}
+
+		if i == 0 {
+			merged, first = schema, sample
+			continue
+		}
+		merged = mergeSchemas(merged, schema, "", func(conflict string) {
+			fmt.Fprintf(os.Stderr, "Warning: %s: %s\n", path, conflict)
+		})
+	}
+	if *describeRanges && len(paths) > 1 {
+		describeObservedRanges(&merged, ranges)
+	}
+	applyArrayBounds(&merged, samples)
+	return merged, first, nil
+}
+
+// Mark every property of an object schema inferred from a single sample as
+// required, throughout the schema, so that merging keeps only the ones all
+// samples have
+func requireAllProperties(schema *Schema) {
+	if len(schema.Properties) > 0 {
+		schema.Required = make([]string, 0, len(schema.Properties))
+		for name, prop := range schema.Properties {
+			schema.Required = append(schema.Required, name)
+			requireAllProperties(&prop)
+			schema.Properties[name] = prop
+		}
+		sort.Strings(schema.Required)
+	}
+	if schema.Items != nil {
+		requireAllProperties(schema.Items)
+	}
+}
+
+// The smallest and largest numbers seen at a key path across samples
+type observedRange struct {
+	min, max float64
+}
+
+// Record the numbers in a decoded sample by key path, using the same
+// paths as schema inference so that they can be matched to the schema
+func observeRanges(value interface{}, path string, ranges map[string]*observedRange) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, item := range v {
+			observeRanges(item, joinKeyPath(path, key), ranges)
+		}
+	case []interface{}:
+		for _, item := range v {
+			observeRanges(item, path+"[]", ranges)
+		}
+	case json.Number:
+		number, err := v.Float64()
+		if err != nil {
+			return
+		}
+		if r, ok := ranges[path]; ok {
+			r.min = math.Min(r.min, number)
+			r.max = math.Max(r.max, number)
+		} else {
+			ranges[path] = &observedRange{number, number}
+		}
+	}
+}
+
+// Describe the numeric properties of a merged schema with the range of
+// values the samples had for them. Only a description is added, so the
+// schema doesn't reject values outside the samples' range.
+func describeObservedRanges(schema *Schema, ranges map[string]*observedRange) {
+	walkSchema(schema, "", func(schema *Schema, location string) {
+		r, ok := ranges[location]
+		if !ok || !isNumericType(schema.Type) {
+			return
+		}
+		description := fmt.Sprintf("observed range %s–%s", formatObservedNumber(r.min), formatObservedNumber(r.max))
+		if schema.Description != "" {
+			description = schema.Description + " (" + description + ")"
+		}
+		schema.Description = description
+	})
+}
+
+// Format a sample number without a trailing .0 or exponent for integers
+func formatObservedNumber(number float64) string {
+	return strconv.FormatFloat(number, 'f', -1, 64)
+}
+
+// Under -infer-array-bounds, set the minItems and maxItems of each array
+// in a schema to the shortest and longest array seen at its key path in
+// the samples it was inferred from. A single sample only sets bounds with
+// -exact-array-bounds, since one length says little about the others.
+func applyArrayBounds(schema *Schema, samples []interface{}) {
+	if !*inferArrayBounds || len(samples) == 0 || (len(samples) == 1 && !*exactArrayBounds) {
+		return
+	}
+
+	lengths := make(map[string]*observedRange)
+	for _, sample := range samples {
+		observeArrayLengths(sample, "", lengths)
+	}
+	walkSchema(schema, "", func(schema *Schema, location string) {
+		r, ok := lengths[location]
+		if !ok || schema.Type != "array" {
+			return
+		}
+		minItems, maxItems := int(r.min), int(r.max)
+		schema.MinItems, schema.MaxItems = &minItems, &maxItems
+	})
+}
+
+// Record the lengths of the arrays in a decoded sample by key path
+func observeArrayLengths(value interface{}, path string, lengths map[string]*observedRange) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, item := range v {
+			observeArrayLengths(item, joinKeyPath(path, key), lengths)
+		}
+	case []interface{}:
+		length := float64(len(v))
+		if r, ok := lengths[path]; ok {
+			r.min = math.Min(r.min, length)
+			r.max = math.Max(r.max, length)
+		} else {
+			lengths[path] = &observedRange{length, length}
+		}
+		for _, item := range v {
+			observeArrayLengths(item, path+"[]", lengths)
+		}
+	}
+}
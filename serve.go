@@ -0,0 +1,68 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"net/http"
+)
+
+var servePort = flag.Int("port", 8080, "port the serve action listens on")
+
+// Swagger UI page loading its assets from a CDN and the spec from
+// /openapi.json
+const swaggerUIPage = `<!DOCTYPE html>
+<html lang="en">
+<head>
+  <meta charset="utf-8">
+  <title>API preview</title>
+  <link rel="stylesheet" href="https://unpkg.com/swagger-ui-dist@5/swagger-ui.css">
+</head>
+<body>
+  <div id="swagger-ui"></div>
+  <script src="https://unpkg.com/swagger-ui-dist@5/swagger-ui-bundle.js"></script>
+  <script>
+    SwaggerUIBundle({url: "/openapi.json", dom_id: "#swagger-ui"});
+  </script>
+</body>
+</html>
+`
+
+// Serve a spec for preview: the spec as JSON at /openapi.json and Swagger
+// UI at /. The file is read again on every request, so edits show up on
+// reload. Runs until the process is stopped.
+func serveSwagger(filePath string) error {
+	// Fail early rather than serving errors when the file can't be read
+	if _, err := readSwaggerFile(filePath); err != nil {
+		return err
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/openapi.json", func(w http.ResponseWriter, r *http.Request) {
+		swagger, err := readSwaggerFile(filePath)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		data, err := marshalSwagger(swagger, true)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", "no-store")
+		w.Write(data)
+	})
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		fmt.Fprint(w, swaggerUIPage)
+	})
+
+	addr := fmt.Sprintf(":%d", *servePort)
+	fmt.Printf("Serving %s at http://localhost%s/ (press Ctrl+C to stop)\n", filePath, addr)
+	server := &http.Server{Addr: addr, Handler: mux}
+	return server.ListenAndServe()
+}
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+var (
+	serverProfile  = flag.String("profile", "", "server profile set-servers applies, e.g. prod; prompted for when not given")
+	serverProfiles = flag.String("profiles", "servers.yaml", "YAML file set-servers reads server profiles from, mapping each profile name to a list of servers with a url and an optional description")
+)
+
+// Add a server to a single operation, for endpoints that live on a
+// different host than the rest of the API
+func addOperationServer(filePath string, reader *bufio.Reader) error {
+	unlock, err := lockFile(filePath)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	swagger, err := readSwaggerFile(filePath)
+	if err != nil {
+		return err
+	}
+
+	path := prompt(reader, "Enter the path of the operation (e.g., /pets): ")
+	method := strings.ToLower(prompt(reader, "Enter HTTP method (get/post/put/delete): "))
+	operation, ok := swagger.Paths[path].Operations[method]
+	if !ok {
+		return fmt.Errorf("operation %s %s not found", strings.ToUpper(method), path)
+	}
+
+	server := Server{
+		URL:         prompt(reader, "Enter the server URL: "),
+		Description: prompt(reader, "Enter a description for the server (optional): "),
+	}
+	if err := validateServerURL(server.URL); err != nil {
+		return err
+	}
+	for _, existing := range operation.Servers {
+		if existing.URL == server.URL {
+			return fmt.Errorf("operation already has server %s", server.URL)
+		}
+	}
+
+	operation.Servers = append(operation.Servers, server)
+	swagger.Paths[path].Operations[method] = operation
+	return writeSwaggerFile(outputFile(filePath), swagger)
+}
+
+// Check a server URL. OpenAPI allows relative URLs and {variable}
+// templates, so only the parts that can be checked are.
+func validateServerURL(serverURL string) error {
+	if serverURL == "" {
+		return fmt.Errorf("server URL is required")
+	}
+	if strings.Contains(serverURL, "{") {
+		return nil
+	}
+	if _, err := url.Parse(serverURL); err != nil {
+		return fmt.Errorf("invalid server URL %q: %w", serverURL, err)
+	}
+	return nil
+}
+
+// Replace the servers of a spec with those of a named profile from the
+// -profiles file, so one source spec can produce a spec per environment
+func setServers(filePath, profile string) error {
+	unlock, err := lockFile(filePath)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	servers, err := loadServerProfile(profile)
+	if err != nil {
+		return err
+	}
+	swagger, err := readSwaggerFile(filePath)
+	if err != nil {
+		return err
+	}
+
+	swagger.Servers = servers
+	fmt.Printf("Set %d servers from profile %s.\n", len(servers), profile)
+	return writeSwaggerFile(outputFile(filePath), swagger)
+}
+
+// Read the servers of one profile from the -profiles file, checking each
+// server URL
+func loadServerProfile(profile string) ([]Server, error) {
+	data, err := ioutil.ReadFile(*serverProfiles)
+	if err != nil {
+		return nil, err
+	}
+	var profiles map[string][]Server
+	if err := yaml.UnmarshalStrict(data, &profiles); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", *serverProfiles, err)
+	}
+
+	servers, ok := profiles[profile]
+	if !ok {
+		return nil, fmt.Errorf("profile %q not found in %s, which has: %s", profile, *serverProfiles, strings.Join(sortedKeys(profiles), ", "))
+	}
+	if len(servers) == 0 {
+		return nil, fmt.Errorf("profile %q in %s has no servers", profile, *serverProfiles)
+	}
+	for _, server := range servers {
+		if err := validateServerURL(server.URL); err != nil {
+			return nil, fmt.Errorf("profile %q in %s: %w", profile, *serverProfiles, err)
+		}
+	}
+	return servers, nil
+}
@@ -0,0 +1,16 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+)
+
+var maxSize = flag.Int("max-size", 0, "refuse to write a spec larger than this many KB, as a guard against schema bloat (0 for no limit)")
+
+// Fail the write of a marshalled spec that is over the -max-size budget
+func checkSizeBudget(filename string, data []byte) error {
+	if *maxSize > 0 && len(data) > *maxSize*1024 {
+		return fmt.Errorf("not writing %s, it is %.1f KB, over the -max-size budget of %d KB", filename, float64(len(data))/1024, *maxSize)
+	}
+	return nil
+}
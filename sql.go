@@ -0,0 +1,226 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+var (
+	sqlLineComment  = regexp.MustCompile(`--[^\n]*`)
+	sqlBlockComment = regexp.MustCompile(`(?s)/\*.*?\*/`)
+	sqlCreateTable  = regexp.MustCompile(`(?is)^CREATE\s+(?:(?:GLOBAL\s+|LOCAL\s+)?(?:TEMP|TEMPORARY)\s+|UNLOGGED\s+)?TABLE\s+(?:IF\s+NOT\s+EXISTS\s+)?([^\s(]+)\s*\((.*)\)[^)]*$`)
+	sqlColumnType   = regexp.MustCompile(`^([A-Za-z_][A-Za-z0-9_]*)\s*(\([^)]*\))?\s*((?:\[\])?)`)
+)
+
+// SQL column types by their first word, upper-cased, and the schemas they
+// map to. Types not listed here fall back to string with a warning.
+var sqlTypes = map[string]Schema{
+	"CHAR": {Type: "string"}, "CHARACTER": {Type: "string"}, "VARCHAR": {Type: "string"},
+	"NCHAR": {Type: "string"}, "NVARCHAR": {Type: "string"}, "TEXT": {Type: "string"},
+	"TINYTEXT": {Type: "string"}, "MEDIUMTEXT": {Type: "string"}, "LONGTEXT": {Type: "string"},
+	"CLOB": {Type: "string"}, "CITEXT": {Type: "string"}, "ENUM": {Type: "string"},
+
+	"TINYINT": {Type: "integer", Format: "int32"}, "SMALLINT": {Type: "integer", Format: "int32"},
+	"MEDIUMINT": {Type: "integer", Format: "int32"}, "INT": {Type: "integer", Format: "int32"},
+	"INTEGER": {Type: "integer", Format: "int32"}, "SERIAL": {Type: "integer", Format: "int32"},
+	"SMALLSERIAL": {Type: "integer", Format: "int32"}, "BIGINT": {Type: "integer", Format: "int64"},
+	"BIGSERIAL": {Type: "integer", Format: "int64"},
+
+	"DECIMAL": {Type: "number"}, "NUMERIC": {Type: "number"}, "MONEY": {Type: "number"},
+	"REAL": {Type: "number", Format: "float"}, "FLOAT": {Type: "number", Format: "float"},
+	"DOUBLE": {Type: "number", Format: "double"},
+
+	"BOOLEAN": {Type: "boolean"}, "BOOL": {Type: "boolean"}, "BIT": {Type: "boolean"},
+
+	"DATE": {Type: "string", Format: "date"}, "TIME": {Type: "string"},
+	"TIMESTAMP": {Type: "string", Format: "date-time"}, "TIMESTAMPTZ": {Type: "string", Format: "date-time"},
+	"DATETIME": {Type: "string", Format: "date-time"},
+
+	"UUID": {Type: "string", Format: "uuid"}, "JSON": {Type: "object"}, "JSONB": {Type: "object"},
+	"BYTEA": {Type: "string", Format: "byte"}, "BLOB": {Type: "string", Format: "byte"},
+	"BINARY": {Type: "string", Format: "byte"}, "VARBINARY": {Type: "string", Format: "byte"},
+}
+
+// Add a component schema to a spec for each CREATE TABLE statement in an
+// SQL schema dump. This is a best-effort converter for common PostgreSQL
+// and MySQL DDL; other statements are ignored, and tables or columns that
+// can't be parsed are reported and skipped.
+func importSQL(filePath, sqlPath string) error {
+	unlock, err := lockFile(filePath)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	swagger, err := readSwaggerFile(filePath)
+	if err != nil {
+		return err
+	}
+	data, err := ioutil.ReadFile(sqlPath)
+	if err != nil {
+		return err
+	}
+
+	if swagger.Components.Schemas == nil {
+		swagger.Components.Schemas = make(map[string]Schema)
+	}
+	var warnings []string
+	imported := 0
+	for _, statement := range splitSQLStatements(string(data)) {
+		if !strings.HasPrefix(strings.ToUpper(statement), "CREATE") || !strings.Contains(strings.ToUpper(statement), "TABLE") {
+			continue
+		}
+		name, schema, tableWarnings, err := sqlTableSchema(statement)
+		if err != nil {
+			warnings = append(warnings, err.Error())
+			continue
+		}
+		warnings = append(warnings, tableWarnings...)
+		swagger.Components.Schemas[name] = schema
+		imported++
+	}
+
+	fmt.Printf("Imported %d SQL tables as component schemas.\n", imported)
+	for _, warning := range warnings {
+		fmt.Println("Warning:", warning)
+	}
+	return writeSwaggerFile(outputFile(filePath), swagger)
+}
+
+// Split an SQL script into statements on semicolons outside quotes, with
+// comments removed
+func splitSQLStatements(script string) []string {
+	script = sqlBlockComment.ReplaceAllString(script, "")
+	script = sqlLineComment.ReplaceAllString(script, "")
+	var statements []string
+	for _, part := range splitOutsideQuotes(script, ';', false) {
+		if part = strings.TrimSpace(part); part != "" {
+			statements = append(statements, part)
+		}
+	}
+	return statements
+}
+
+// Split s on sep wherever it is outside quotes, and outside parentheses
+// too when topLevel is set
+func splitOutsideQuotes(s string, sep rune, topLevel bool) []string {
+	var parts []string
+	var quote rune
+	depth, start := 0, 0
+	for i, r := range s {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case r == '\'' || r == '"' || r == '`':
+			quote = r
+		case r == '(':
+			depth++
+		case r == ')':
+			depth--
+		case r == sep && (!topLevel || depth == 0):
+			parts = append(parts, s[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, s[start:])
+}
+
+// Build the component schema for a CREATE TABLE statement, returning its
+// component name and warnings about columns it couldn't map
+func sqlTableSchema(statement string) (string, Schema, []string, error) {
+	match := sqlCreateTable.FindStringSubmatch(statement)
+	if match == nil {
+		return "", Schema{}, nil, fmt.Errorf("could not parse statement: %s", sqlSnippet(statement))
+	}
+	table := unquoteSQLName(match[1][strings.LastIndex(match[1], ".")+1:])
+	name := sqlComponentName(table)
+	if !isValidComponentName(name) {
+		return "", Schema{}, nil, fmt.Errorf("table %s: no valid component name", table)
+	}
+
+	schema := Schema{Type: "object", Properties: make(map[string]Schema)}
+	var warnings []string
+	var required []string
+	for _, definition := range splitOutsideQuotes(match[2], ',', true) {
+		definition = strings.TrimSpace(definition)
+		fields := strings.Fields(definition)
+		if len(fields) == 0 {
+			continue
+		}
+
+		switch strings.ToUpper(fields[0]) {
+		case "PRIMARY":
+			// A table-level PRIMARY KEY (a, b) makes its columns required
+			if open := strings.Index(definition, "("); open >= 0 {
+				for _, column := range strings.Split(strings.Trim(definition[open:], "()"), ",") {
+					required = append(required, unquoteSQLName(strings.TrimSpace(column)))
+				}
+			}
+			continue
+		case "CONSTRAINT", "FOREIGN", "UNIQUE", "KEY", "INDEX", "CHECK", "EXCLUDE", "FULLTEXT":
+			continue
+		}
+
+		column := unquoteSQLName(fields[0])
+		rest := strings.TrimSpace(definition[len(fields[0]):])
+		typeMatch := sqlColumnType.FindStringSubmatch(rest)
+		if typeMatch == nil {
+			warnings = append(warnings, fmt.Sprintf("%s: could not parse column definition %q", table, definition))
+			continue
+		}
+
+		columnSchema, ok := sqlTypes[strings.ToUpper(typeMatch[1])]
+		if !ok {
+			warnings = append(warnings, fmt.Sprintf("%s.%s: unknown type %s, using string", table, column, strings.TrimSpace(typeMatch[0])))
+			columnSchema = Schema{Type: "string"}
+		}
+		if typeMatch[3] != "" {
+			items := columnSchema
+			columnSchema = Schema{Type: "array", Items: &items}
+		}
+		schema.Properties[column] = columnSchema
+
+		constraints := strings.ToUpper(strings.Join(strings.Fields(rest[len(typeMatch[0]):]), " "))
+		if strings.Contains(constraints, "NOT NULL") || strings.Contains(constraints, "PRIMARY KEY") {
+			required = append(required, column)
+		}
+	}
+
+	for _, column := range required {
+		if _, ok := schema.Properties[column]; ok && !containsString(schema.Required, column) {
+			schema.Required = append(schema.Required, column)
+		}
+	}
+	return name, schema, warnings, nil
+}
+
+// Strip the quoting from an SQL identifier: "name", `name` or [name]
+func unquoteSQLName(name string) string {
+	return strings.Trim(name, "\"`[]")
+}
+
+// Turn a table name into a component name, e.g. order_items becomes
+// OrderItems
+func sqlComponentName(table string) string {
+	var sb strings.Builder
+	for _, word := range strings.FieldsFunc(table, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		sb.WriteString(capitalize(strings.ToLower(word)))
+	}
+	return sb.String()
+}
+
+// The start of a statement, for messages
+func sqlSnippet(statement string) string {
+	statement = strings.Join(strings.Fields(statement), " ")
+	if len(statement) > 60 {
+		return statement[:60] + "..."
+	}
+	return statement
+}
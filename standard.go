@@ -0,0 +1,157 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+
+	"gopkg.in/yaml.v2"
+)
+
+var standardResponsesConfig = flag.String("standard-responses", "", "YAML file configuring apply-standard-responses: the error schema name, media type and status codes with their descriptions")
+
+// The set of error responses apply-standard-responses adds. Statuses with a
+// blank description get the usual reason phrase.
+type standardResponses struct {
+	Schema    string            `yaml:"schema"`
+	MediaType string            `yaml:"mediaType"`
+	Responses map[string]string `yaml:"responses"`
+}
+
+// The built-in standard set, which a -standard-responses file overrides
+// field by field
+func defaultStandardResponses() standardResponses {
+	standard := standardResponses{
+		Schema:    "Error",
+		MediaType: "application/json",
+		Responses: map[string]string{"400": "", "401": "", "403": "", "404": "", "500": ""},
+	}
+	if *problemDetails {
+		standard.MediaType = problemJSONMediaType
+	}
+	return standard
+}
+
+// Load the standard response set, applying the -standard-responses file
+// when one is given
+func loadStandardResponses() (standardResponses, error) {
+	standard := defaultStandardResponses()
+	if *standardResponsesConfig == "" {
+		return standard, nil
+	}
+
+	data, err := ioutil.ReadFile(*standardResponsesConfig)
+	if err != nil {
+		return standard, err
+	}
+	var config standardResponses
+	if err := yaml.UnmarshalStrict(data, &config); err != nil {
+		return standard, fmt.Errorf("parsing %s: %w", *standardResponsesConfig, err)
+	}
+	if config.Schema != "" {
+		standard.Schema = config.Schema
+	}
+	if config.MediaType != "" {
+		standard.MediaType = config.MediaType
+	}
+	if config.Responses != nil {
+		standard.Responses = config.Responses
+	}
+
+	if !isValidComponentName(standard.Schema) {
+		return standard, fmt.Errorf("invalid schema name %q in %s", standard.Schema, *standardResponsesConfig)
+	}
+	for status := range standard.Responses {
+		if !statusCodePattern.MatchString(status) {
+			return standard, fmt.Errorf("invalid status code %q in %s", status, *standardResponsesConfig)
+		}
+	}
+	return standard, nil
+}
+
+// Add the standard error responses to every operation that lacks them,
+// each referring to a shared error schema in components. Responses an
+// operation already documents are left as they are.
+func applyStandardResponses(filePath string) error {
+	unlock, err := lockFile(filePath)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	standard, err := loadStandardResponses()
+	if err != nil {
+		return err
+	}
+	swagger, err := readSwaggerFile(filePath)
+	if err != nil {
+		return err
+	}
+
+	added := addStandardResponses(swagger, standard)
+	fmt.Printf("Added %d responses.\n", added)
+	if added == 0 {
+		return nil
+	}
+	return writeSwaggerFile(outputFile(filePath), swagger)
+}
+
+// Add the standard responses to a spec, returning how many were added. The
+// error schema is created when it doesn't exist yet and a response uses
+// it; 204 and 304 responses have no content, so they don't.
+func addStandardResponses(swagger *SwaggerTemplate, standard standardResponses) int {
+	added := 0
+	usesSchema := false
+	for _, path := range sortedKeys(swagger.Paths) {
+		for _, method := range sortedKeys(swagger.Paths[path].Operations) {
+			operation := swagger.Paths[path].Operations[method]
+			for _, status := range sortedKeys(standard.Responses) {
+				if _, exists := operation.Responses[status]; exists {
+					continue
+				}
+				description := standard.Responses[status]
+				if description == "" {
+					description = responseDescription(status)
+				}
+				if operation.Responses == nil {
+					operation.Responses = make(map[string]Response)
+				}
+				response := Response{Description: description}
+				if !isBodilessStatus(status) {
+					response.Content = map[string]MediaType{
+						standard.MediaType: {Schema: Schema{Ref: schemaRefPrefix + standard.Schema}},
+					}
+					usesSchema = true
+				}
+				operation.Responses[status] = response
+				added++
+			}
+			swagger.Paths[path].Operations[method] = operation
+		}
+	}
+
+	if _, exists := swagger.Components.Schemas[standard.Schema]; usesSchema && !exists {
+		if swagger.Components.Schemas == nil {
+			swagger.Components.Schemas = make(map[string]Schema)
+		}
+		swagger.Components.Schemas[standard.Schema] = defaultErrorSchema()
+	}
+	return added
+}
+
+// The error schema created for standard responses when the spec has none:
+// RFC 7807 problem details with -problem-details, otherwise a code and a
+// message
+func defaultErrorSchema() Schema {
+	if *problemDetails {
+		return problemDetailsSchema(Schema{})
+	}
+	return Schema{
+		Type:     "object",
+		Required: []string{"code", "message"},
+		Properties: map[string]Schema{
+			"code":    {Type: "integer"},
+			"message": {Type: "string"},
+		},
+	}
+}
@@ -0,0 +1,147 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+var streamSamples = flag.Bool("stream", false, "have update read a sample file holding a top-level JSON array one element at a time, merging the schemas of all elements, so large files aren't loaded into memory")
+
+// Prompt for a sample file and infer the schema of the JSON array in it by
+// streaming its elements
+func readStreamedSampleSchema(reader *bufio.Reader) (Schema, error) {
+	filePath := prompt(reader, "Enter the JSON file path: ")
+	f, err := os.Open(filePath)
+	if err != nil {
+		return Schema{}, err
+	}
+	defer f.Close()
+	return streamArraySchema(f)
+}
+
+// Infer the schema of a top-level JSON array, decoding one element at a
+// time and merging each element's schema into the item schema, so memory
+// use is bounded by the largest element rather than the whole array. The
+// result is the same as inferring the array in memory.
+func streamArraySchema(r io.Reader) (Schema, error) {
+	decoder := json.NewDecoder(r)
+	decoder.UseNumber()
+
+	token, err := decoder.Token()
+	if err != nil {
+		return Schema{}, err
+	}
+	if delim, ok := token.(json.Delim); !ok || delim != '[' {
+		return Schema{}, fmt.Errorf("-stream needs a top-level JSON array")
+	}
+
+	var items Schema
+	count := 0
+	for decoder.More() {
+		var element interface{}
+		if err := decoder.Decode(&element); err != nil {
+			return Schema{}, fmt.Errorf("element %d: %w", count, err)
+		}
+		schema, err := inferSchema(element, "[]", 1)
+		if err != nil {
+			return Schema{}, err
+		}
+		if count == 0 {
+			items = schema
+		} else {
+			items = mergeItemSchema(items, schema, "[]")
+		}
+		count++
+	}
+	if _, err := decoder.Token(); err != nil {
+		return Schema{}, err
+	}
+
+	if count == 0 {
+		if *strictTypes {
+			return Schema{}, fmt.Errorf("cannot infer item type of empty array at %s", displayKeyPath(""))
+		}
+		warnInference("", "empty array, item type defaulted to string")
+		items = Schema{Type: "string"}
+	}
+	return Schema{Type: "array", Items: &items}, nil
+}
+
+// Merge two schemas inferred from samples of the same value at a key path.
+// Objects get the union of their properties and require only what both
+// require, integer and number widen to number, a null sample makes the
+// other schema nullable, and otherwise conflicting types keep the first
+// schema's type. Conflicts are passed to report unless it is nil.
+func mergeSchemas(a, b Schema, path string, report func(string)) Schema {
+	if b.nullSample {
+		a.Nullable = true
+		return a
+	}
+	if a.nullSample {
+		b.Nullable = true
+		return b
+	}
+
+	if a.Type != b.Type {
+		if !isNumericType(a.Type) || !isNumericType(b.Type) {
+			if report != nil {
+				report(fmt.Sprintf("%s is %s in one sample and %s in another, keeping %s", displayKeyPath(path), a.Type, b.Type, a.Type))
+			}
+			return a
+		}
+		a.Type = "number"
+	}
+
+	if a.Format != b.Format {
+		a.Format = mergeFormats(a.Format, b.Format)
+	}
+	a.Nullable = a.Nullable || b.Nullable
+	a.UniqueItems = a.UniqueItems && b.UniqueItems
+
+	var required []string
+	for _, name := range a.Required {
+		if containsString(b.Required, name) {
+			required = append(required, name)
+		}
+	}
+	a.Required = required
+
+	if len(b.Properties) > 0 {
+		merged := make(map[string]Schema, len(a.Properties)+len(b.Properties))
+		for name, prop := range a.Properties {
+			merged[name] = prop
+		}
+		for _, name := range sortedKeys(b.Properties) {
+			prop := b.Properties[name]
+			if existing, ok := merged[name]; ok {
+				prop = mergeSchemas(existing, prop, joinKeyPath(path, name), report)
+			}
+			merged[name] = prop
+		}
+		a.Properties = merged
+	}
+	if a.Items != nil && b.Items != nil {
+		items := mergeSchemas(*a.Items, *b.Items, path+"[]", report)
+		a.Items = &items
+	}
+	return a
+}
+
+// Report whether a schema type is integer or number
+func isNumericType(schemaType string) bool {
+	return schemaType == "integer" || schemaType == "number"
+}
+
+// Pick the format that covers two inferred formats: int64 covers int32,
+// and otherwise differing formats are dropped
+func mergeFormats(a, b string) string {
+	if strings.HasPrefix(a, "int") && strings.HasPrefix(b, "int") {
+		return "int64"
+	}
+	return ""
+}
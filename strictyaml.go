@@ -0,0 +1,57 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strconv"
+	"strings"
+
+	yamlv3 "gopkg.in/yaml.v3"
+)
+
+var strictYAML = flag.Bool("strict-yaml", false, "fail to read a YAML spec that repeats a key in a mapping, instead of silently keeping the last value")
+
+// Fail with the line and column of every mapping key that repeats an
+// earlier key in a YAML document. yaml.v2 keeps the last value of a
+// repeated key without saying so and has no positions, so the document is
+// decoded into yaml.v3 nodes, which hold every key as written along with
+// where it was written.
+func checkDuplicateKeys(data []byte) error {
+	var document yamlv3.Node
+	if err := yamlv3.Unmarshal(data, &document); err != nil {
+		return err
+	}
+
+	var duplicates []string
+	findDuplicateKeys(&document, "", &duplicates)
+	if len(duplicates) > 0 {
+		return fmt.Errorf("%d duplicate keys found:\n  %s", len(duplicates), strings.Join(duplicates, "\n  "))
+	}
+	return nil
+}
+
+// Record the duplicate keys of a YAML node and its children. Aliases
+// aren't followed, since their keys were checked where they're anchored.
+func findDuplicateKeys(node *yamlv3.Node, path string, duplicates *[]string) {
+	switch node.Kind {
+	case yamlv3.DocumentNode:
+		for _, child := range node.Content {
+			findDuplicateKeys(child, path, duplicates)
+		}
+	case yamlv3.MappingNode:
+		seen := make(map[string]*yamlv3.Node, len(node.Content)/2)
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key := node.Content[i]
+			if first, ok := seen[key.Value]; ok {
+				*duplicates = append(*duplicates, fmt.Sprintf("line %d, column %d: %s: key %q repeats the one on line %d", key.Line, key.Column, displayKeyPath(path), key.Value, first.Line))
+			} else {
+				seen[key.Value] = key
+			}
+			findDuplicateKeys(node.Content[i+1], joinKeyPath(path, key.Value), duplicates)
+		}
+	case yamlv3.SequenceNode:
+		for i, item := range node.Content {
+			findDuplicateKeys(item, path+"["+strconv.Itoa(i)+"]", duplicates)
+		}
+	}
+}
@@ -0,0 +1,141 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"strings"
+)
+
+// Check a JSON sample against the schema of an operation's response,
+// printing each mismatch: wrong types, values outside an enum, missing
+// required properties, properties a schema with additionalProperties
+// false doesn't list, and unlisted properties that don't match an
+// additionalProperties schema
+func testResponse(filePath, path, method, status, samplePath string) error {
+	swagger, err := readSwaggerFile(filePath)
+	if err != nil {
+		return err
+	}
+
+	operation, ok := swagger.Paths[path].Operations[method]
+	if !ok {
+		return fmt.Errorf("operation %s %s not found", strings.ToUpper(method), path)
+	}
+	response, ok := operation.Responses[status]
+	if !ok {
+		return fmt.Errorf("operation %s %s has no %s response", strings.ToUpper(method), path, status)
+	}
+	media, ok := response.Content["application/json"]
+	if !ok {
+		return fmt.Errorf("the %s response has no application/json content", status)
+	}
+
+	data, err := ioutil.ReadFile(samplePath)
+	if err != nil {
+		return err
+	}
+	var sample interface{}
+	if err := decodeJSON(data, &sample); err != nil {
+		return fmt.Errorf("parsing %s: %w", samplePath, err)
+	}
+
+	mismatches := sampleMismatches(swagger, media.Schema, sample, "", nil)
+	for _, mismatch := range mismatches {
+		fmt.Println("  " + mismatch)
+	}
+	if len(mismatches) > 0 {
+		return fmt.Errorf("%s doesn't match the %s response schema: %d mismatches", samplePath, status, len(mismatches))
+	}
+	fmt.Printf("%s matches the %s response schema.\n", samplePath, status)
+	return nil
+}
+
+// Compare a decoded JSON value with a schema, returning a description of
+// each mismatch. path is the key path of the value, and refs the components
+// being expanded, so that recursive schemas stop instead of looping.
+func sampleMismatches(swagger *SwaggerTemplate, schema Schema, value interface{}, path string, refs []string) []string {
+	if schema.Ref != "" {
+		name := strings.TrimPrefix(schema.Ref, schemaRefPrefix)
+		target, ok := swagger.Components.Schemas[name]
+		if !ok || containsString(refs, name) {
+			return nil
+		}
+		return sampleMismatches(swagger, target, value, path, append(refs, name))
+	}
+
+	location := displayKeyPath(path)
+	if value == nil {
+		if schema.Nullable || schema.nullType || enumAllowsNull(schema.Enum) || schema.Type == "" {
+			return nil
+		}
+		return []string{fmt.Sprintf("%s: is null, expected %s", location, schema.Type)}
+	}
+
+	if len(schema.Enum) > 0 && !enumContains(schema.Enum, value) {
+		return []string{fmt.Sprintf("%s: %s is not one of %s", location, formatEnumValues([]interface{}{value})[0], strings.Join(formatEnumValues(schema.Enum), ", "))}
+	}
+
+	if actual := jsonValueType(value); schema.Type != "" && actual != schema.Type && !(schema.Type == "number" && actual == "integer") {
+		return []string{fmt.Sprintf("%s: is %s, expected %s", location, actual, schema.Type)}
+	}
+
+	var mismatches []string
+	switch v := value.(type) {
+	case []interface{}:
+		if schema.Items != nil {
+			for i, item := range v {
+				mismatches = append(mismatches, sampleMismatches(swagger, *schema.Items, item, fmt.Sprintf("%s[%d]", path, i), refs)...)
+			}
+		}
+	case map[string]interface{}:
+		for _, name := range schema.Required {
+			if _, ok := v[name]; !ok {
+				mismatches = append(mismatches, fmt.Sprintf("%s: missing required property %q", joinKeyPath(path, name), name))
+			}
+		}
+		for _, name := range sortedKeys(v) {
+			prop, known := schema.Properties[name]
+			switch {
+			case known:
+				mismatches = append(mismatches, sampleMismatches(swagger, prop, v[name], joinKeyPath(path, name), refs)...)
+			case schema.AdditionalProperties != nil && schema.AdditionalProperties.Schema != nil:
+				mismatches = append(mismatches, sampleMismatches(swagger, *schema.AdditionalProperties.Schema, v[name], joinKeyPath(path, name), refs)...)
+			case schema.AdditionalProperties.Forbidden():
+				mismatches = append(mismatches, fmt.Sprintf("%s: property %q is not in the schema, which doesn't allow additional properties", joinKeyPath(path, name), name))
+			}
+		}
+	}
+	return mismatches
+}
+
+// The schema type of a value decoded with decodeJSON
+func jsonValueType(value interface{}) string {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		return "object"
+	case []interface{}:
+		return "array"
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	case json.Number:
+		if _, err := v.Int64(); err == nil {
+			return "integer"
+		}
+		return "number"
+	}
+	return "unknown"
+}
+
+// Report whether a decoded JSON value is one of an enum's values
+func enumContains(values []interface{}, value interface{}) bool {
+	literal := formatEnumValues([]interface{}{value})[0]
+	for _, allowed := range formatEnumValues(values) {
+		if allowed == literal {
+			return true
+		}
+	}
+	return false
+}
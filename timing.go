@@ -0,0 +1,18 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"time"
+)
+
+var timing = flag.Bool("timing", false, "print how long the parse, generate, validate and write phases take to stderr")
+
+// Report the time since start for a phase when -timing is on. Meant to be
+// deferred at the top of the phase: defer timePhase("parse", time.Now())
+func timePhase(phase string, start time.Time) {
+	if *timing {
+		fmt.Fprintf(os.Stderr, "timing: %s took %s\n", phase, time.Since(start).Round(time.Microsecond))
+	}
+}
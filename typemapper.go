@@ -0,0 +1,57 @@
+package main
+
+import (
+	"encoding/json"
+	"reflect"
+)
+
+// A TypeMapper decides the type and format of the scalar values in samples
+// during schema inference: strings, numbers, which are json.Number, and
+// booleans. path is the key path of the value, as built by joinKeyPath.
+// ok is false for a value the mapper has no rule for, which inference then
+// reports as an unsupported kind and documents as a string.
+type TypeMapper interface {
+	MapType(path string, value interface{}) (schemaType, format string, ok bool)
+}
+
+// TypeMapperFunc lets a plain function be used as a TypeMapper
+type TypeMapperFunc func(path string, value interface{}) (schemaType, format string, ok bool)
+
+func (f TypeMapperFunc) MapType(path string, value interface{}) (string, string, bool) {
+	return f(path, value)
+}
+
+// The mapper schema inference uses. Code embedding the inference can
+// replace it, typically with a mapper that handles a few cases and defers
+// to DefaultTypeMapper for the rest, e.g. to document every int64 as a
+// string for JavaScript clients.
+var SchemaTypeMapper TypeMapper = DefaultTypeMapper{}
+
+// The built-in mapping the CLI uses, including the rules -int-format,
+// -coerce-bools and -infer-binary turn on
+type DefaultTypeMapper struct{}
+
+func (DefaultTypeMapper) MapType(path string, value interface{}) (string, string, bool) {
+	switch v := value.(type) {
+	case json.Number:
+		schema := numberSchema(v)
+		return schema.Type, schema.Format, true
+	case string:
+		// Only the exact JSON tokens count, so "True" or "yes" stay strings
+		if *coerceBools && (v == "true" || v == "false") {
+			return "boolean", "", true
+		}
+		if *inferBinary {
+			if format := inferBinaryFormat(path, v); format != "" {
+				return "string", format, true
+			}
+		}
+		if looksLikeURI(v) {
+			return "string", "uri", true
+		}
+		return "string", "", true
+	}
+
+	schemaType, ok := getSwaggerType(reflect.ValueOf(value).Kind())
+	return schemaType, "", ok
+}
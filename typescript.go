@@ -0,0 +1,161 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// Matches property names that can be written unquoted in TypeScript
+var tsIdentifierPattern = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+
+// Write a TypeScript interface for every component schema, to outputPath
+// or to stdout when it is empty
+func exportTypeScript(filePath, outputPath string) error {
+	swagger, err := readSwaggerFile(filePath)
+	if err != nil {
+		return err
+	}
+
+	source := formatTypeScript(swagger.Components.Schemas)
+	if outputPath == "" {
+		fmt.Print(source)
+		return nil
+	}
+	if err := ioutil.WriteFile(outputPath, []byte(source), 0644); err != nil {
+		return err
+	}
+	fmt.Println("TypeScript file written successfully.")
+	return nil
+}
+
+// Format component schemas as TypeScript declarations. Object schemas
+// become interfaces; anything else becomes a type alias.
+func formatTypeScript(schemas map[string]Schema) string {
+	var sb strings.Builder
+	sb.WriteString("// Generated from the OpenAPI component schemas. Do not edit.\n")
+	for _, name := range sortedKeys(schemas) {
+		schema := schemas[name]
+		sb.WriteString("\n")
+		if doc := tsDeprecation(schema); doc != "" {
+			sb.WriteString(doc + "\n")
+		}
+		if schema.Type == "object" && len(schema.Properties) > 0 && !schema.Nullable {
+			fmt.Fprintf(&sb, "export interface %s %s\n", tsTypeName(name), tsObjectType(schema, ""))
+		} else {
+			fmt.Fprintf(&sb, "export type %s = %s;\n", tsTypeName(name), tsType(schema, ""))
+		}
+	}
+	return sb.String()
+}
+
+// Turn a component name into a TypeScript type name, e.g. pet-list
+// becomes PetList
+func tsTypeName(name string) string {
+	var sb strings.Builder
+	for _, word := range strings.FieldsFunc(name, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_'
+	}) {
+		sb.WriteString(capitalize(word))
+	}
+	if sb.Len() == 0 || unicode.IsDigit([]rune(sb.String())[0]) {
+		return "Schema" + sb.String()
+	}
+	return sb.String()
+}
+
+// The JSDoc comment marking a deprecated schema, naming its sunset date
+// when it has one, or "" when it isn't deprecated
+func tsDeprecation(schema Schema) string {
+	if !schema.Deprecated {
+		return ""
+	}
+	if schema.Sunset != "" {
+		return "/** @deprecated Removed on " + schema.Sunset + ". */"
+	}
+	return "/** @deprecated */"
+}
+
+// Format the TypeScript type of a schema. indent is the indentation of the
+// line the type starts on, used for nested object types.
+func tsType(schema Schema, indent string) string {
+	nullable := schema.Nullable || schema.nullType
+	var t string
+	switch {
+	case schema.Ref != "":
+		t = tsTypeName(strings.TrimPrefix(schema.Ref, schemaRefPrefix))
+	case schema.Const != nil || len(schema.Enum) > 0:
+		values := schema.Enum
+		if schema.Const != nil {
+			values = []interface{}{schema.Const}
+		}
+		var literals []string
+		for _, value := range values {
+			if value == nil {
+				nullable = true
+				continue
+			}
+			normalized, err := normalizeYAMLValue(value)
+			if err != nil {
+				continue
+			}
+			if literal, err := json.Marshal(normalized); err == nil {
+				literals = append(literals, string(literal))
+			}
+		}
+		t = strings.Join(literals, " | ")
+	case schema.Type == "string":
+		t = "string"
+	case schema.Type == "integer" || schema.Type == "number":
+		t = "number"
+	case schema.Type == "boolean":
+		t = "boolean"
+	case schema.Type == "array":
+		item := "unknown"
+		if schema.Items != nil {
+			item = tsType(*schema.Items, indent)
+		}
+		if strings.Contains(item, " | ") {
+			item = "(" + item + ")"
+		}
+		t = item + "[]"
+	case schema.Type == "object" && len(schema.Properties) > 0:
+		t = tsObjectType(schema, indent)
+	case schema.Type == "object":
+		t = "Record<string, unknown>"
+	default:
+		t = "unknown"
+	}
+
+	if nullable {
+		t += " | null"
+	}
+	return t
+}
+
+// Format an object schema as a TypeScript object type, marking properties
+// that aren't required as optional
+func tsObjectType(schema Schema, indent string) string {
+	var sb strings.Builder
+	sb.WriteString("{\n")
+	for _, name := range sortedKeys(schema.Properties) {
+		key := name
+		if !tsIdentifierPattern.MatchString(name) {
+			quoted, _ := json.Marshal(name)
+			key = string(quoted)
+		}
+		if !containsString(schema.Required, name) {
+			key += "?"
+		}
+		prop := schema.Properties[name]
+		if doc := tsDeprecation(prop); doc != "" {
+			fmt.Fprintf(&sb, "%s  %s\n", indent, doc)
+		}
+		fmt.Fprintf(&sb, "%s  %s: %s;\n", indent, key, tsType(prop, indent+"  "))
+	}
+	sb.WriteString(indent + "}")
+	return sb.String()
+}
@@ -0,0 +1,343 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+var (
+	httpMethods = []string{"get", "put", "post", "delete", "options", "head", "patch", "trace"}
+
+	schemaTypes = []string{"string", "integer", "number", "boolean", "array", "object"}
+
+	statusCodePattern = regexp.MustCompile(`^(default|[1-5](\d\d|XX))$`)
+
+	strictMode = flag.Bool("strict", false, "refuse to write a spec that fails validation")
+)
+
+// Check a spec for structural problems, returning a description of each
+// one found. An empty result means the spec is valid.
+func validateSwagger(swagger *SwaggerTemplate) []string {
+	defer timePhase("validate", time.Now())
+
+	var problems []string
+
+	if !strings.HasPrefix(swagger.OpenAPI, "3.") {
+		problems = append(problems, fmt.Sprintf("openapi: unsupported version %q, expected 3.x", swagger.OpenAPI))
+	}
+	for _, key := range []string{"title", "version"} {
+		if value, _ := swagger.Info[key].(string); value == "" {
+			problems = append(problems, fmt.Sprintf("info.%s: missing", key))
+		}
+	}
+
+	if terms, ok := swagger.Info["termsOfService"].(string); ok {
+		if err := checkTermsOfService(terms); err != nil {
+			problems = append(problems, "info.termsOfService: not a URL")
+		}
+	}
+	if _, ok := swagger.Info["summary"]; ok && !supportsInfoSummary(swagger.OpenAPI) {
+		problems = append(problems, "info.summary: only allowed in OpenAPI 3.1")
+	}
+
+	problems = append(problems, validateExtensions(swagger.Extensions, "", passthroughKeys)...)
+	problems = append(problems, validateExtensions(swagger.Components.Extensions, "components", componentPassthroughKeys)...)
+
+	// Servers are optional at both levels; operations without their own
+	// fall back to the global list, which itself defaults to "/"
+	problems = append(problems, validateServers(swagger.Servers, "servers")...)
+
+	for _, path := range sortedKeys(swagger.Paths) {
+		if !strings.HasPrefix(path, "/") {
+			problems = append(problems, fmt.Sprintf("paths.%s: path must start with '/'", path))
+		}
+		problems = append(problems, validateParameters(swagger.Paths[path].Parameters, "paths."+path)...)
+		for _, method := range sortedKeys(swagger.Paths[path].Operations) {
+			location := fmt.Sprintf("paths.%s.%s", path, method)
+			if !containsString(httpMethods, method) {
+				problems = append(problems, fmt.Sprintf("%s: unknown HTTP method", location))
+			}
+			operation := swagger.Paths[path].Operations[method]
+			problems = append(problems, validateOperation(operation, location)...)
+			problems = append(problems, validatePathParameters(path, effectiveParameters(swagger.Paths[path], operation), location)...)
+		}
+	}
+
+	for _, name := range sortedKeys(swagger.Components.Schemas) {
+		problems = append(problems, validateSchema(swagger.Components.Schemas[name], "components.schemas."+name)...)
+	}
+	problems = append(problems, validateTypeLists(swagger)...)
+	problems = append(problems, validateLinks(swagger)...)
+	return append(problems, danglingRefs(swagger)...)
+}
+
+// Check a spec with validateSwagger and, with -deep-validate, the
+// kin-openapi validator as well
+func validateSpec(swagger *SwaggerTemplate) ([]string, error) {
+	problems := validateSwagger(swagger)
+	deep, err := deepValidateSwagger(swagger)
+	if err != nil {
+		return nil, err
+	}
+	return append(problems, deep...), nil
+}
+
+// Check a single operation
+func validateOperation(operation Operation, location string) []string {
+	problems := validateServers(operation.Servers, location+".servers")
+	problems = append(problems, validateExtensions(operation.Extensions, location, operationPassthroughKeys)...)
+	problems = append(problems, validateParameters(operation.Parameters, location)...)
+
+	if operation.RequestBody != nil {
+		for _, mediaType := range sortedKeys(operation.RequestBody.Content) {
+			media := operation.RequestBody.Content[mediaType]
+			problems = append(problems, validateSchema(media.Schema, location+".requestBody."+mediaType)...)
+			problems = append(problems, validateExtensions(media.Extensions, location+".requestBody."+mediaType, mediaTypePassthroughKeys)...)
+			problems = append(problems, validateEncoding(mediaType, media, location+".requestBody."+mediaType)...)
+		}
+	}
+
+	if len(operation.Responses) == 0 {
+		problems = append(problems, location+".responses: at least one response is required")
+	}
+	for _, status := range sortedKeys(operation.Responses) {
+		response := operation.Responses[status]
+		responseLocation := fmt.Sprintf("%s.responses.%s", location, status)
+		if !statusCodePattern.MatchString(status) {
+			problems = append(problems, responseLocation+": invalid status code")
+		}
+		if response.Description == "" {
+			problems = append(problems, responseLocation+": missing description")
+		}
+		problems = append(problems, validateExtensions(response.Extensions, responseLocation, responsePassthroughKeys)...)
+		for _, mediaType := range sortedKeys(response.Content) {
+			media := response.Content[mediaType]
+			problems = append(problems, validateSchema(media.Schema, responseLocation+"."+mediaType)...)
+			problems = append(problems, validateExtensions(media.Extensions, responseLocation+"."+mediaType, mediaTypePassthroughKeys)...)
+		}
+	}
+	return problems
+}
+
+// Check the parameters of an operation or path
+func validateParameters(params []Parameter, location string) []string {
+	var problems []string
+	for i, param := range params {
+		paramLocation := fmt.Sprintf("%s.parameters[%d]", location, i)
+		if param.Name == "" {
+			problems = append(problems, paramLocation+": missing name")
+		}
+		switch param.In {
+		case "path":
+			if !param.Required {
+				problems = append(problems, paramLocation+": path parameters must be required")
+			}
+		case "query", "header", "cookie":
+		default:
+			problems = append(problems, fmt.Sprintf("%s: invalid location %q", paramLocation, param.In))
+		}
+		if param.Schema != nil {
+			problems = append(problems, validateSchema(*param.Schema, paramLocation+".schema")...)
+		}
+		problems = append(problems, validateExtensions(param.Extensions, paramLocation, parameterPassthroughKeys)...)
+	}
+	return problems
+}
+
+// Check a list of servers
+func validateServers(servers []Server, location string) []string {
+	var problems []string
+	for i, server := range servers {
+		if err := validateServerURL(server.URL); err != nil {
+			problems = append(problems, fmt.Sprintf("%s[%d]: %v", location, i, err))
+		}
+	}
+	return problems
+}
+
+// Check a schema and everything nested in it
+func validateSchema(schema Schema, location string) []string {
+	if schema.Ref != "" {
+		return nil
+	}
+
+	var problems []string
+	if schema.Type != "" && !containsString(schemaTypes, schema.Type) {
+		problems = append(problems, fmt.Sprintf("%s: unknown type %q", location, schema.Type))
+	}
+	problems = append(problems, validateExtensions(schema.Extensions, location, schemaPassthroughKeys)...)
+	if schema.MinProperties != nil && schema.MaxProperties != nil && *schema.MinProperties > *schema.MaxProperties {
+		problems = append(problems, fmt.Sprintf("%s: minProperties %d is more than maxProperties %d", location, *schema.MinProperties, *schema.MaxProperties))
+	}
+	if schema.Type == "array" {
+		if schema.Items == nil {
+			problems = append(problems, location+": array schema is missing items")
+		} else {
+			problems = append(problems, validateSchema(*schema.Items, location+"[]")...)
+		}
+	}
+	for _, name := range sortedKeys(schema.Properties) {
+		problems = append(problems, validateSchema(schema.Properties[name], joinKeyPath(location, name))...)
+	}
+	if schema.AdditionalProperties != nil && schema.AdditionalProperties.Schema != nil {
+		problems = append(problems, validateSchema(*schema.AdditionalProperties.Schema, location+".additionalProperties")...)
+	}
+	return problems
+}
+
+// Cross-check the {name} placeholders in a path against the operation's
+// path parameters that apply to it, reporting placeholders without a
+// parameter and parameters that don't appear in the path
+func validatePathParameters(path string, params []Parameter, location string) []string {
+	var problems []string
+
+	var placeholders []string
+	for _, match := range pathParamPattern.FindAllStringSubmatch(path, -1) {
+		placeholders = append(placeholders, match[1])
+	}
+
+	var declared []string
+	for _, param := range params {
+		if param.In == "path" {
+			declared = append(declared, param.Name)
+		}
+	}
+
+	for _, name := range placeholders {
+		if !containsString(declared, name) {
+			problems = append(problems, fmt.Sprintf("%s.parameters: missing path parameter %q used in the path", location, name))
+		}
+	}
+	for _, name := range declared {
+		if !containsString(placeholders, name) {
+			problems = append(problems, fmt.Sprintf("%s.parameters: path parameter %q doesn't appear in the path", location, name))
+		}
+	}
+	return problems
+}
+
+// OpenAPI keys that are passed through as they are rather than modelled, so
+// they aren't reported as unknown: at the top level, on operations,
+// responses, parameters and media types, under components and on schemas
+var (
+	passthroughKeys          = []string{"externalDocs", "jsonSchemaDialect", "security", "tags", "webhooks"}
+	operationPassthroughKeys = []string{"externalDocs", "security"}
+	responsePassthroughKeys  = []string{"headers"}
+	parameterPassthroughKeys = []string{"$ref", "allowEmptyValue", "allowReserved", "content", "deprecated", "example", "examples", "explode", "style"}
+	mediaTypePassthroughKeys = []string{"examples"}
+	componentPassthroughKeys = []string{"callbacks", "examples", "headers", "links", "parameters", "pathItems", "requestBodies", "responses", "securitySchemes"}
+	schemaPassthroughKeys    = []string{
+		"$comment", "$defs", "$id", "$schema", "anyOf", "contains", "contentEncoding", "contentMediaType",
+		"dependentRequired", "dependentSchemas", "discriminator", "else", "examples", "exclusiveMaximum",
+		"exclusiveMinimum", "externalDocs", "if", "maxContains", "maximum", "maxLength", "minContains",
+		"minimum", "minLength", "multipleOf", "not", "oneOf", "pattern", "patternProperties", "prefixItems",
+		"propertyNames", "readOnly", "then", "unevaluatedItems", "unevaluatedProperties", "writeOnly", "xml",
+	}
+)
+
+// Check that keys kept as extensions are x- vendor extensions or known
+// passthrough keys. Anything else is most likely a typo; it is reported but
+// still preserved.
+func validateExtensions(extensions map[string]interface{}, location string, known []string) []string {
+	var problems []string
+	for _, key := range sortedKeys(extensions) {
+		if strings.HasPrefix(key, "x-") || containsString(known, key) {
+			continue
+		}
+		if location != "" {
+			key = location + "." + key
+		}
+		problems = append(problems, fmt.Sprintf("%s: unknown key, extensions must start with x-", key))
+	}
+	return problems
+}
+
+// Validate a single Swagger YAML file, printing each problem found
+func validateSwaggerFile(filePath string) error {
+	swagger, err := readSwaggerFile(filePath)
+	if err != nil {
+		return err
+	}
+
+	problems, err := validateSpec(swagger)
+	if err != nil {
+		return err
+	}
+	for _, problem := range problems {
+		fmt.Println("  " + problem)
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("%s has %d problems", filePath, len(problems))
+	}
+
+	fmt.Println("Swagger file is valid.")
+	return nil
+}
+
+// Validate every spec under a directory whose file name matches pattern,
+// printing PASS or FAIL per file. Files that fail to parse count as
+// failures without stopping the walk.
+func validateDir(dir, pattern string) error {
+	if _, err := filepath.Match(pattern, ""); err != nil {
+		return fmt.Errorf("invalid pattern %q: %w", pattern, err)
+	}
+
+	total, failed := 0, 0
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+		if matched, _ := filepath.Match(pattern, info.Name()); !matched {
+			return nil
+		}
+
+		total++
+		swagger, err := readSwaggerFile(path)
+		if err != nil {
+			failed++
+			fmt.Printf("FAIL %s\n  %v\n", path, err)
+			return nil
+		}
+		problems, err := validateSpec(swagger)
+		if err != nil {
+			return err
+		}
+		if len(problems) > 0 {
+			failed++
+			fmt.Printf("FAIL %s\n", path)
+			for _, problem := range problems {
+				fmt.Println("  " + problem)
+			}
+			return nil
+		}
+		fmt.Printf("PASS %s\n", path)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("\n%d files checked: %d passed, %d failed.\n", total, total-failed, failed)
+	if failed > 0 {
+		return fmt.Errorf("%d of %d files failed validation", failed, total)
+	}
+	return nil
+}
+
+// Report whether a list contains a string
+func containsString(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,77 @@
+package main
+
+import "fmt"
+
+// Call fn on every schema in the spec: parameter, request body and response
+// schemas of each operation, the component schemas, and everything nested
+// inside them. Changes fn makes through the pointer are stored back into the
+// spec. location describes where each schema lives, for use in messages.
+func walkSpecSchemas(swagger *SwaggerTemplate, fn func(schema *Schema, location string)) {
+	for _, path := range sortedKeys(swagger.Paths) {
+		walkParameterSchemas(swagger.Paths[path].Parameters, "paths."+path, fn)
+		for _, method := range sortedKeys(swagger.Paths[path].Operations) {
+			operation := swagger.Paths[path].Operations[method]
+			walkOperationSchemas(&operation, fmt.Sprintf("paths.%s.%s", path, method), fn)
+			swagger.Paths[path].Operations[method] = operation
+		}
+	}
+
+	for _, name := range sortedKeys(swagger.Components.Schemas) {
+		schema := swagger.Components.Schemas[name]
+		walkSchema(&schema, "components.schemas."+name, fn)
+		swagger.Components.Schemas[name] = schema
+	}
+}
+
+// Call fn on every schema used by an operation
+func walkOperationSchemas(operation *Operation, location string, fn func(schema *Schema, location string)) {
+	walkParameterSchemas(operation.Parameters, location, fn)
+
+	if operation.RequestBody != nil {
+		walkContentSchemas(operation.RequestBody.Content, location+".requestBody", fn)
+	}
+
+	for _, status := range sortedKeys(operation.Responses) {
+		response := operation.Responses[status]
+		walkContentSchemas(response.Content, fmt.Sprintf("%s.responses.%s", location, status), fn)
+		operation.Responses[status] = response
+	}
+}
+
+// Call fn on the schema of each parameter in a list
+func walkParameterSchemas(params []Parameter, location string, fn func(schema *Schema, location string)) {
+	for i := range params {
+		if params[i].Schema != nil {
+			walkSchema(params[i].Schema, fmt.Sprintf("%s.parameters[%d].schema", location, i), fn)
+		}
+	}
+}
+
+// Call fn on the schema of each media type in a content map
+func walkContentSchemas(content map[string]MediaType, location string, fn func(schema *Schema, location string)) {
+	for _, mediaType := range sortedKeys(content) {
+		media := content[mediaType]
+		walkSchema(&media.Schema, location+"."+mediaType, fn)
+		content[mediaType] = media
+	}
+}
+
+// Call fn on a schema and then on every schema nested inside it
+func walkSchema(schema *Schema, location string, fn func(schema *Schema, location string)) {
+	fn(schema, location)
+
+	for _, name := range sortedKeys(schema.Properties) {
+		prop := schema.Properties[name]
+		walkSchema(&prop, joinKeyPath(location, name), fn)
+		schema.Properties[name] = prop
+	}
+	if schema.Items != nil {
+		walkSchema(schema.Items, location+"[]", fn)
+	}
+	if schema.AdditionalProperties != nil && schema.AdditionalProperties.Schema != nil {
+		walkSchema(schema.AdditionalProperties.Schema, location+".additionalProperties", fn)
+	}
+	for i := range schema.AllOf {
+		walkSchema(&schema.AllOf[i], fmt.Sprintf("%s.allOf[%d]", location, i), fn)
+	}
+}
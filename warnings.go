@@ -0,0 +1,40 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+)
+
+var (
+	werror        = flag.Bool("Werror", false, "have update fail after writing the file when schema inference had to guess, e.g. for empty arrays or null values")
+	werrorNoWrite = flag.Bool("Werror-no-write", false, "like -Werror, but don't write the file when there are inference warnings")
+)
+
+// Warnings from schema inference in the current action, for -Werror
+var inferenceWarnings []string
+
+// Record and print a warning about a value whose type inference had to
+// guess, at a key path as built by joinKeyPath
+func warnInference(path, message string) {
+	recordInferenceWarning(displayKeyPath(path) + ": " + message)
+}
+
+// Record and print an inference warning, once however many array elements
+// or samples it applies to
+func recordInferenceWarning(warning string) {
+	if containsString(inferenceWarnings, warning) {
+		return
+	}
+	inferenceWarnings = append(inferenceWarnings, warning)
+	fmt.Fprintln(os.Stderr, "Warning:", warning)
+}
+
+// The error -Werror or -Werror-no-write turns the inference warnings into,
+// or nil when neither flag is set or there were no warnings
+func inferenceWarningsError() error {
+	if (!*werror && !*werrorNoWrite) || len(inferenceWarnings) == 0 {
+		return nil
+	}
+	return fmt.Errorf("%d schema inference warnings with -Werror", len(inferenceWarnings))
+}